@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/rivo/tview"
+)
+
+// Statuses of a finished background task
+const (
+	taskCompleted = "completed"
+	taskCancelled = "cancelled"
+	taskFailed    = "failed"
+)
+
+// taskResult is the outcome of a background task, passed to its render function on the UI goroutine
+type taskResult struct {
+	Status  string        // taskCompleted, taskCancelled or taskFailed
+	Err     error         // Why the task failed, nil when it completed or was cancelled
+	Elapsed time.Duration // Time since the task started
+	Visible bool          // False when the user navigated away while the task ran, nothing should be shown then
+}
+
+// isBackgroundTaskRunning reports whether a cancellable background task owns the body
+func isBackgroundTaskRunning() bool {
+	return cmd.UiState.CancelBackgroundTask != nil
+}
+
+// cancelBackgroundTask cancels the running background task, if any
+func cancelBackgroundTask() bool {
+	if cmd.UiState.CancelBackgroundTask == nil {
+		return false
+	}
+	logger.Logger.Debug().Msg("[Background] Cancelling running task")
+	cmd.UiState.CancelBackgroundTask()
+	return true
+}
+
+// runBackgroundTask shows a progress view titled title and runs work in the background, ESC cancels its context.
+// work changes the state shown by progress through update, which runs the change on the UI goroutine and redraws the view.
+// Once work returns, render receives the result on the UI goroutine, e.g. to log it and show it when still visible.
+func runBackgroundTask(title string, progress func(elapsed time.Duration) string, work func(ctx context.Context, update func(func())) error, render func(result taskResult)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.UiState.CancelBackgroundTask = cancel
+	navigationDepth := len(cmd.UiState.NavigationStack)
+
+	progressView := tview.NewTextView().SetDynamicColors(true)
+	progressView.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s ", title)).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderPadding(1, 1, 2, 2)
+
+	start := time.Now()
+	progressView.SetText(progress(0))
+
+	Body = progressView
+	updateRootView(nil)
+	App.SetFocus(Body)
+
+	update := func(change func()) {
+		App.QueueUpdateDraw(func() {
+			change()
+			progressView.SetText(progress(time.Since(start)))
+		})
+	}
+
+	go func() {
+		err := work(ctx, update)
+
+		App.QueueUpdateDraw(func() {
+			cmd.UiState.CancelBackgroundTask = nil
+			cancel()
+
+			result := taskResult{
+				Status:  taskCompleted,
+				Elapsed: time.Since(start).Round(time.Second),
+				Visible: Body == progressView && len(cmd.UiState.NavigationStack) == navigationDepth,
+			}
+			if err == context.Canceled {
+				result.Status = taskCancelled
+			} else if err != nil {
+				result.Status = taskFailed
+				result.Err = err
+			}
+			render(result)
+		})
+	}()
+}
+
+// showTaskResult shows the message of a finished task followed by its error, OK shows previousBody again
+func showTaskResult(message string, err error, previousBody tview.Primitive) {
+	if err != nil {
+		message = fmt.Sprintf("%s\n\n%s", message, strings.Join(strings.Fields(err.Error()), " "))
+	}
+
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: message,
+		LeftChoice: ui.ModalChoice{
+			Name: "OK",
+			Handler: func(*tview.Flex) {
+				Body = previousBody
+				updateRootView(nil)
+				App.SetFocus(Body)
+			},
+		},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
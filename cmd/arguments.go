@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultsConfigurationFileName is the configuration file holding global defaults instead of a resource
+var DefaultsConfigurationFileName = "defaults.yaml"
+
+const argumentFlagPrefix = "--"
+
+// Defaults holds the arguments shared by every resource
+type Defaults struct {
+	Arguments []string            `yaml:"arguments"` // Arguments appended to every command unless it sets the same flag
+	Templates map[string][]string `yaml:"templates"` // Named argument sets commands can include
+	// Profiles (names or patterns like "prod-*") where actions writing to AWS, e.g. editing DynamoDB items, are disabled
+	ReadOnlyProfiles []string `yaml:"readOnlyProfiles"`
+}
+
+var GlobalDefaults = Defaults{}
+
+// IsReadOnlyProfile reports whether writes are disabled for the profile
+func IsReadOnlyProfile(profile string) bool {
+	for _, pattern := range GlobalDefaults.ReadOnlyProfiles {
+		if matched, err := path.Match(pattern, profile); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadErrors collects the problems found while loading and expanding configurations
+var LoadErrors []error
+
+func loadDefaults(filename string) (Defaults, error) {
+	defaults := Defaults{}
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaults, nil
+		}
+		return defaults, err
+	}
+	if err := yaml.Unmarshal(yamlFile, &defaults); err != nil {
+		return defaults, fmt.Errorf("error while unmarshalling %s: %w", filename, err)
+	}
+	return defaults, nil
+}
+
+// expandResourceArguments replaces the arguments of every command with the result of merging,
+// from lowest to highest precedence: global defaults, resource defaults, included templates and
+// the command arguments.
+func expandResourceArguments(resource *Resource, defaults Defaults) []error {
+	var errs []error
+
+	for index := range resource.Commands {
+		command := &resource.Commands[index]
+
+		// AWS CLI defaults do not apply to plugin commands, they only get their own templates
+		layers := [][]string{defaults.Arguments, resource.Defaults}
+		if command.IsPlugin() {
+			layers = [][]string{}
+		}
+		for _, templateName := range command.Include {
+			template, exists := resource.Templates[templateName]
+			if !exists {
+				template, exists = defaults.Templates[templateName]
+			}
+			if !exists {
+				errs = append(errs, fmt.Errorf("%s/%s: unknown argument template %q", resource.Name, command.Name, templateName))
+				continue
+			}
+			layers = append(layers, template)
+		}
+		layers = append(layers, command.Arguments)
+
+		command.Arguments = mergeArguments(layers...)
+	}
+
+	return errs
+}
+
+// mergeArguments merges argument lists given in increasing precedence.
+// A flag and its values set by a higher layer replace the same flag of every lower layer.
+// The result lists the highest layer first, so command specific arguments stay in front.
+func mergeArguments(layers ...[]string) []string {
+	seenFlags := make(map[string]bool)
+	merged := []string{}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, group := range groupArguments(layers[i]) {
+			flag := group[0]
+			if strings.HasPrefix(flag, argumentFlagPrefix) {
+				if seenFlags[flag] {
+					continue
+				}
+				seenFlags[flag] = true
+			}
+			merged = append(merged, group...)
+		}
+	}
+
+	return merged
+}
+
+// groupArguments splits arguments into groups made of a flag followed by its values.
+// Leading values without a flag form their own groups.
+func groupArguments(arguments []string) [][]string {
+	var groups [][]string
+	for _, argument := range arguments {
+		if strings.HasPrefix(argument, argumentFlagPrefix) || len(groups) == 0 ||
+			!strings.HasPrefix(groups[len(groups)-1][0], argumentFlagPrefix) {
+			groups = append(groups, []string{argument})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], argument)
+	}
+	return groups
+}
+
+// NewCommand returns a command built at runtime (e.g. a DynamoDB write), with the defaults of the resource
+func (resource *Resource) NewCommand(name string, arguments ...string) Command {
+	return Command{Name: name, Arguments: mergeArguments(GlobalDefaults.Arguments, resource.Defaults, arguments)}
+}
+
+// WithArguments returns a copy of the command with the arguments added, replacing the configured values of the same flags
+func (command *Command) WithArguments(arguments ...string) Command {
+	updated := *command
+	updated.Arguments = mergeArguments(command.Arguments, arguments)
+	return updated
+}
+
+// ArgumentValue returns the value following the flag in the command arguments
+func (command *Command) ArgumentValue(flag string) (string, bool) {
+	for _, group := range groupArguments(command.Arguments) {
+		if group[0] == flag && len(group) == 2 {
+			return group[1], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeArguments(t *testing.T) {
+	globalDefaults := []string{"--output", "json", "--cli-read-timeout", "2", "--cli-connect-timeout", "5"}
+	resourceDefaults := []string{"--cli-read-timeout", "5"}
+	template := []string{"--limit", "50"}
+	arguments := []string{"--table-name", "$TABLENAME", "--cli-read-timeout", "10"}
+
+	merged := mergeArguments(globalDefaults, resourceDefaults, template, arguments)
+
+	expected := []string{"--table-name", "$TABLENAME", "--cli-read-timeout", "10", "--limit", "50", "--output", "json", "--cli-connect-timeout", "5"}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Got %v, expected %v", merged, expected)
+	}
+}
+
+func TestMergeArgumentsKeepsFlagsWithoutValues(t *testing.T) {
+	merged := mergeArguments([]string{"--no-paginate", "--output", "json"}, []string{"--attribute-names", "All", "QueueArn"})
+
+	expected := []string{"--attribute-names", "All", "QueueArn", "--no-paginate", "--output", "json"}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Got %v, expected %v", merged, expected)
+	}
+}
+
+func TestCommandWithArguments(t *testing.T) {
+	command := Command{Name: "query", Arguments: []string{"--table-name", "users", "--limit", "50"}}
+
+	updated := command.WithArguments("--consistent-read", "--limit", "100")
+
+	expected := []string{"--consistent-read", "--limit", "100", "--table-name", "users"}
+	if !reflect.DeepEqual(updated.Arguments, expected) {
+		t.Errorf("Got %v, expected %v", updated.Arguments, expected)
+	}
+	if value, ok := command.ArgumentValue("--limit"); !ok || value != "50" {
+		t.Errorf("Expected the configured limit to be kept, got %q", value)
+	}
+	if _, ok := updated.ArgumentValue("--no-scan-index-forward"); ok {
+		t.Error("Expected no value for a missing flag")
+	}
+}
+
+func TestExpandResourceArguments(t *testing.T) {
+	resource := Resource{
+		Name:      "dynamodb",
+		Defaults:  []string{"--cli-read-timeout", "5"},
+		Templates: map[string][]string{"page": {"--limit", "25"}},
+		Commands: []Command{
+			{Name: "scan", Include: []string{"page", "json"}, Arguments: []string{"--table-name", "$TABLENAME"}},
+			{Name: "query", Include: []string{"missing"}},
+		},
+	}
+	defaults := Defaults{
+		Arguments: []string{"--cli-read-timeout", "2"},
+		Templates: map[string][]string{"page": {"--limit", "50"}, "json": {"--output", "json"}},
+	}
+
+	errs := expandResourceArguments(&resource, defaults)
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error for the unknown template, got %v", errs)
+	}
+	expected := []string{"--table-name", "$TABLENAME", "--output", "json", "--limit", "25", "--cli-read-timeout", "5"}
+	if !reflect.DeepEqual(resource.Commands[0].Arguments, expected) {
+		t.Errorf("Got %v, expected %v", resource.Commands[0].Arguments, expected)
+	}
+}
+
+func TestIsReadOnlyProfile(t *testing.T) {
+	previous := GlobalDefaults
+	defer func() { GlobalDefaults = previous }()
+	GlobalDefaults = Defaults{ReadOnlyProfiles: []string{"production", "prod-*"}}
+
+	profiles := map[string]bool{
+		"production":  true,
+		"prod-eu":     true,
+		"staging":     false,
+		"preprod-eu":  false,
+		"production2": false,
+	}
+	for profile, expected := range profiles {
+		if IsReadOnlyProfile(profile) != expected {
+			t.Errorf("%s: expected read-only %t", profile, expected)
+		}
+	}
+}
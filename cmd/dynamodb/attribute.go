@@ -0,0 +1,82 @@
+package dynamodb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AttributeValue is a typed DynamoDB value, marshalled to DynamoDB JSON (e.g. {"S": "foo"}) by encoding/json
+type AttributeValue struct {
+	S    *string                   `json:"S,omitempty"`
+	N    *string                   `json:"N,omitempty"`
+	B    *string                   `json:"B,omitempty"` // Base64 encoded
+	BOOL *bool                     `json:"BOOL,omitempty"`
+	NULL *bool                     `json:"NULL,omitempty"`
+	SS   []string                  `json:"SS,omitempty"`
+	NS   []string                  `json:"NS,omitempty"`
+	BS   []string                  `json:"BS,omitempty"`
+	M    map[string]AttributeValue `json:"M,omitempty"`
+	L    []AttributeValue          `json:"L,omitempty"`
+}
+
+// MarshalJSON keeps empty maps and lists, which omitempty would drop
+func (value AttributeValue) MarshalJSON() ([]byte, error) {
+	switch {
+	case value.M != nil:
+		return json.Marshal(map[string]map[string]AttributeValue{"M": value.M})
+	case value.L != nil:
+		return json.Marshal(map[string][]AttributeValue{"L": value.L})
+	}
+	type plain AttributeValue
+	return json.Marshal(plain(value))
+}
+
+var numberRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// NewAttributeValue parses user input as a value of the given scalar type (S, N, B, BOOL or NULL)
+func NewAttributeValue(attrType string, input string) (AttributeValue, error) {
+	switch attrType {
+	case "S":
+		return AttributeValue{S: &input}, nil
+	case "N":
+		number := strings.TrimSpace(input)
+		if !numberRegex.MatchString(number) {
+			return AttributeValue{}, fmt.Errorf("%q is not a number", input)
+		}
+		return AttributeValue{N: &number}, nil
+	case "B":
+		if _, err := base64.StdEncoding.DecodeString(input); err != nil || input == "" {
+			return AttributeValue{}, fmt.Errorf("binary values must be base64 encoded, got %q", input)
+		}
+		return AttributeValue{B: &input}, nil
+	case "BOOL":
+		var value bool
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		default:
+			return AttributeValue{}, fmt.Errorf("BOOL value must be true or false, got %q", input)
+		}
+		return AttributeValue{BOOL: &value}, nil
+	case "NULL":
+		null := true
+		return AttributeValue{NULL: &null}, nil
+	}
+	return AttributeValue{}, fmt.Errorf("unsupported value type %q", attrType)
+}
+
+// NewKeyValue parses user input as a key attribute value, key attributes can only be S, N or B and never empty
+func NewKeyValue(attrType string, input string) (AttributeValue, error) {
+	if attrType != "S" && attrType != "N" && attrType != "B" {
+		return AttributeValue{}, fmt.Errorf("unsupported key type %q", attrType)
+	}
+	if input == "" {
+		return AttributeValue{}, fmt.Errorf("key values can not be empty")
+	}
+	return NewAttributeValue(attrType, input)
+}
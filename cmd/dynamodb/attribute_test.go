@@ -0,0 +1,64 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewAttributeValueMarshalling(t *testing.T) {
+	values := map[string]struct {
+		attrType string
+		input    string
+		expected string
+	}{
+		"string with quotes": {"S", `say "hi" \ bye`, `{"S":"say \"hi\" \\ bye"}`},
+		"empty string":       {"S", "", `{"S":""}`},
+		"number":             {"N", " -1.5e3 ", `{"N":"-1.5e3"}`},
+		"binary":             {"B", "aGVsbG8=", `{"B":"aGVsbG8="}`},
+		"bool":               {"BOOL", "False", `{"BOOL":false}`},
+		"null":               {"NULL", "", `{"NULL":true}`},
+	}
+
+	for name, value := range values {
+		attribute, err := NewAttributeValue(value.attrType, value.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		marshalled, _ := json.Marshal(attribute)
+		if string(marshalled) != value.expected {
+			t.Errorf("%s: got %s, expected %s", name, marshalled, value.expected)
+		}
+	}
+}
+
+func TestNewAttributeValueValidation(t *testing.T) {
+	invalid := map[string][2]string{
+		"number":      {"N", "12abc"},
+		"binary":      {"B", "not base64!"},
+		"bool":        {"BOOL", "yes"},
+		"unknown":     {"X", "value"},
+		"empty key":   {"S", ""},
+		"boolean key": {"BOOL", "true"},
+	}
+
+	for name, value := range invalid {
+		if _, err := NewKeyValue(value[0], value[1]); err == nil {
+			t.Errorf("%s: expected an error for %q", name, value[1])
+		}
+	}
+}
+
+func TestAttributeValueRoundTrip(t *testing.T) {
+	input := `{"M":{"empty":{"L":[]},"tags":{"SS":["a","b"]},"nested":{"M":{}}}}`
+
+	var attribute AttributeValue
+	if err := json.Unmarshal([]byte(input), &attribute); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	marshalled, _ := json.Marshal(attribute)
+	if string(marshalled) != `{"M":{"empty":{"L":[]},"nested":{"M":{}},"tags":{"SS":["a","b"]}}}` {
+		t.Errorf("Unexpected round trip: %s", marshalled)
+	}
+}
@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// BackupColumns are the columns of the backups table, the JSON viewer shows the ARNs and the table details
+var BackupColumns = []string{"BackupName", "BackupStatus", "BackupType", "BackupCreationDateTime", "BackupSizeBytes", "BackupExpiryDateTime"}
+
+// BackupStatusAvailable is the status of a backup that can be restored
+const BackupStatusAvailable = "AVAILABLE"
+
+// PointInTimeRecoveryEnabled is the status of a table that can be restored to a point in time
+const PointInTimeRecoveryEnabled = "ENABLED"
+
+// namePattern is the pattern of table and backup names
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,255}$`)
+
+// BackupSummary is a backup of the list-backups output
+type BackupSummary struct {
+	TableName              string    `json:"TableName"`
+	BackupArn              string    `json:"BackupArn"`
+	BackupName             string    `json:"BackupName"`
+	BackupCreationDateTime Timestamp `json:"BackupCreationDateTime"`
+	BackupStatus           string    `json:"BackupStatus"`
+	BackupType             string    `json:"BackupType"`
+	BackupSizeBytes        int64     `json:"BackupSizeBytes"`
+}
+
+// ParseBackupSummary decodes a row of the backups table
+func ParseBackupSummary(data []byte) (BackupSummary, error) {
+	var backup BackupSummary
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return BackupSummary{}, err
+	}
+	if backup.BackupArn == "" {
+		return BackupSummary{}, fmt.Errorf("the row is not a backup, it has no BackupArn")
+	}
+	return backup, nil
+}
+
+// ValidateTableName checks a table name before a restore creates the table
+func ValidateTableName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("table names have 3 to 255 letters, digits, '_', '-' or '.'")
+	}
+	return nil
+}
+
+// DefaultBackupName names a backup after its table and creation time, e.g. "orders-20240501-103000"
+func DefaultBackupName(tableName string, now time.Time) string {
+	return fmt.Sprintf("%s-%s", tableName, now.UTC().Format("20060102-150405"))
+}
+
+// CreateBackupArguments returns the create-backup arguments of an on-demand backup of the table
+func CreateBackupArguments(tableName string, backupName string) ([]string, error) {
+	backupName = strings.TrimSpace(backupName)
+	if !namePattern.MatchString(backupName) {
+		return nil, fmt.Errorf("backup names have 3 to 255 letters, digits, '_', '-' or '.'")
+	}
+	return []string{"--table-name", tableName, "--backup-name", backupName}, nil
+}
+
+// RestoreBackupArguments returns the restore-table-from-backup arguments restoring the backup into a new table
+func RestoreBackupArguments(backup BackupSummary, targetTableName string) ([]string, error) {
+	if backup.BackupStatus != BackupStatusAvailable {
+		return nil, fmt.Errorf("%s is %s, only %s backups can be restored", backup.BackupName, backup.BackupStatus, BackupStatusAvailable)
+	}
+	targetTableName = strings.TrimSpace(targetTableName)
+	if err := ValidateTableName(targetTableName); err != nil {
+		return nil, err
+	}
+	return []string{"--target-table-name", targetTableName, "--backup-arn", backup.BackupArn}, nil
+}
+
+// IsPointInTimeRecoveryEnabled reports whether the table can be restored to a point in time
+func (backups ContinuousBackups) IsPointInTimeRecoveryEnabled() bool {
+	return backups.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus == PointInTimeRecoveryEnabled
+}
+
+// RecoverySummary describes the point-in-time recovery of the table, e.g. "PITR enabled, restorable from ... to ..."
+func (backups ContinuousBackups) RecoverySummary() string {
+	recovery := backups.PointInTimeRecoveryDescription
+	if !backups.IsPointInTimeRecoveryEnabled() {
+		return fmt.Sprintf("PITR %s", strings.ToLower(recovery.PointInTimeRecoveryStatus))
+	}
+	return fmt.Sprintf("PITR enabled, restorable from %s to %s", recovery.EarliestRestorableDateTime, recovery.LatestRestorableDateTime)
+}
+
+// PointInTimeRestore restores a table as it was at a point in time into a new table
+type PointInTimeRestore struct {
+	SourceTableName string
+	TargetTableName string
+	RestoreDateTime string // RFC 3339 date, empty for the latest restorable time
+}
+
+// Arguments returns the restore-table-to-point-in-time arguments, the date must be in the restorable period of the backups
+func (restore PointInTimeRestore) Arguments(backups ContinuousBackups) ([]string, error) {
+	if !backups.IsPointInTimeRecoveryEnabled() {
+		return nil, fmt.Errorf("point-in-time recovery is not enabled on %s", restore.SourceTableName)
+	}
+	targetTableName := strings.TrimSpace(restore.TargetTableName)
+	if err := ValidateTableName(targetTableName); err != nil {
+		return nil, err
+	}
+	if targetTableName == restore.SourceTableName {
+		return nil, fmt.Errorf("restore into a new table, %s exists", targetTableName)
+	}
+	arguments := []string{"--source-table-name", restore.SourceTableName, "--target-table-name", targetTableName}
+
+	restoreDateTime := strings.TrimSpace(restore.RestoreDateTime)
+	if restoreDateTime == "" {
+		return append(arguments, "--use-latest-restorable-time"), nil
+	}
+	date, err := time.Parse(time.RFC3339, restoreDateTime)
+	if err != nil {
+		return nil, fmt.Errorf("the restore time must be an RFC 3339 date, e.g. 2024-05-01T10:30:00Z")
+	}
+
+	recovery := backups.PointInTimeRecoveryDescription
+	earliest, earliestErr := time.Parse(time.RFC3339, string(recovery.EarliestRestorableDateTime))
+	latest, latestErr := time.Parse(time.RFC3339, string(recovery.LatestRestorableDateTime))
+	if earliestErr == nil && latestErr == nil && (date.Before(earliest) || date.After(latest)) {
+		return nil, fmt.Errorf("the restore time must be between %s and %s", recovery.EarliestRestorableDateTime, recovery.LatestRestorableDateTime)
+	}
+	return append(arguments, "--restore-date-time", date.Format(time.RFC3339)), nil
+}
+
+// ParseRestoredTable returns the name and status of the table created by a restore
+func ParseRestoredTable(output string) (string, string, error) {
+	var result struct {
+		TableDescription *TableDescription `json:"TableDescription"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return "", "", err
+	}
+	if result.TableDescription == nil {
+		return "", "", fmt.Errorf("restore output has no TableDescription attribute")
+	}
+	return result.TableDescription.TableName, result.TableDescription.TableStatus, nil
+}
+
+// ParseCreatedBackup returns the name and status of the backup created by create-backup
+func ParseCreatedBackup(output string) (string, string, error) {
+	var result struct {
+		BackupDetails *struct {
+			BackupName   string `json:"BackupName"`
+			BackupStatus string `json:"BackupStatus"`
+		} `json:"BackupDetails"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return "", "", err
+	}
+	if result.BackupDetails == nil {
+		return "", "", fmt.Errorf("create-backup output has no BackupDetails attribute")
+	}
+	return result.BackupDetails.BackupName, result.BackupDetails.BackupStatus, nil
+}
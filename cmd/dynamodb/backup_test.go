@@ -0,0 +1,115 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const continuousBackupsOutput = `{"ContinuousBackupsDescription": {
+	"ContinuousBackupsStatus": "ENABLED",
+	"PointInTimeRecoveryDescription": {
+		"PointInTimeRecoveryStatus": "ENABLED",
+		"RecoveryPeriodInDays": 35,
+		"EarliestRestorableDateTime": "2024-04-01T00:00:00.000000+00:00",
+		"LatestRestorableDateTime": "2024-05-01T10:00:00.123000+00:00"
+	}
+}}`
+
+func TestBackupArguments(t *testing.T) {
+	if name := DefaultBackupName("orders", time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)); name != "orders-20240501-103000" {
+		t.Errorf("Unexpected backup name %q", name)
+	}
+
+	arguments, err := CreateBackupArguments("orders", " nightly ")
+	if err != nil || !reflect.DeepEqual(arguments, []string{"--table-name", "orders", "--backup-name", "nightly"}) {
+		t.Errorf("Unexpected create-backup arguments %v (%v)", arguments, err)
+	}
+	if _, err := CreateBackupArguments("orders", "a b"); err == nil {
+		t.Errorf("Expected an error for a backup name with a space")
+	}
+
+	backup, err := ParseBackupSummary([]byte(`{"BackupArn": "arn:backup", "BackupName": "nightly", "BackupStatus": "AVAILABLE", "BackupSizeBytes": 10}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	arguments, err = RestoreBackupArguments(backup, "orders-restored")
+	if err != nil || !reflect.DeepEqual(arguments, []string{"--target-table-name", "orders-restored", "--backup-arn", "arn:backup"}) {
+		t.Errorf("Unexpected restore arguments %v (%v)", arguments, err)
+	}
+	if _, err := RestoreBackupArguments(backup, "x"); err == nil {
+		t.Errorf("Expected an error for a too short table name")
+	}
+	backup.BackupStatus = "CREATING"
+	if _, err := RestoreBackupArguments(backup, "orders-restored"); err == nil || !strings.Contains(err.Error(), "CREATING") {
+		t.Errorf("Expected an error for a backup being created, got %v", err)
+	}
+
+	if _, err := ParseBackupSummary([]byte(`{"TableName": "orders"}`)); err == nil {
+		t.Errorf("Expected an error for a row without BackupArn")
+	}
+}
+
+func TestPointInTimeRestoreArguments(t *testing.T) {
+	backups, err := ParseContinuousBackups(continuousBackupsOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary := backups.RecoverySummary(); !strings.HasPrefix(summary, "PITR enabled, restorable from 2024-04-01") {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+
+	restore := PointInTimeRestore{SourceTableName: "orders", TargetTableName: "orders-restored"}
+	arguments, err := restore.Arguments(backups)
+	expected := []string{"--source-table-name", "orders", "--target-table-name", "orders-restored", "--use-latest-restorable-time"}
+	if err != nil || !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Expected %v, got %v (%v)", expected, arguments, err)
+	}
+
+	restore.RestoreDateTime = "2024-04-15T12:00:00+02:00"
+	arguments, err = restore.Arguments(backups)
+	if err != nil || arguments[len(arguments)-1] != "2024-04-15T12:00:00+02:00" || arguments[len(arguments)-2] != "--restore-date-time" {
+		t.Errorf("Unexpected arguments %v (%v)", arguments, err)
+	}
+
+	tests := []struct {
+		name    string
+		restore PointInTimeRestore
+	}{
+		{"Before the earliest time", PointInTimeRestore{SourceTableName: "orders", TargetTableName: "orders-restored", RestoreDateTime: "2024-03-01T00:00:00Z"}},
+		{"After the latest time", PointInTimeRestore{SourceTableName: "orders", TargetTableName: "orders-restored", RestoreDateTime: "2024-06-01T00:00:00Z"}},
+		{"Invalid date", PointInTimeRestore{SourceTableName: "orders", TargetTableName: "orders-restored", RestoreDateTime: "yesterday"}},
+		{"Same table", PointInTimeRestore{SourceTableName: "orders", TargetTableName: "orders"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.restore.Arguments(backups); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+
+	backups.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus = "DISABLED"
+	if _, err := restore.Arguments(backups); err == nil {
+		t.Errorf("Expected an error without point-in-time recovery")
+	}
+	if summary := backups.RecoverySummary(); summary != "PITR disabled" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+}
+
+func TestParseBackupOutputs(t *testing.T) {
+	name, status, err := ParseCreatedBackup(`{"BackupDetails": {"BackupArn": "arn", "BackupName": "nightly", "BackupStatus": "CREATING"}}`)
+	if err != nil || name != "nightly" || status != "CREATING" {
+		t.Errorf("Unexpected backup %q %q (%v)", name, status, err)
+	}
+
+	name, status, err = ParseRestoredTable(`{"TableDescription": {"TableName": "orders-restored", "TableStatus": "CREATING"}}`)
+	if err != nil || name != "orders-restored" || status != "CREATING" {
+		t.Errorf("Unexpected table %q %q (%v)", name, status, err)
+	}
+	if _, _, err := ParseRestoredTable(`{}`); err == nil {
+		t.Errorf("Expected an error without TableDescription")
+	}
+}
@@ -0,0 +1,363 @@
+package dynamodb
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Statuses of an item in a table comparison
+const (
+	CompareOnlyLeft  = "Only left"
+	CompareOnlyRight = "Only right"
+	CompareDiffers   = "Differs"
+)
+
+// Sides of a table comparison
+const (
+	CompareLeft  = 0
+	CompareRight = 1
+)
+
+// CompareSide is one of the compared tables, an empty region uses the region of the profile
+type CompareSide struct {
+	Profile   string
+	Region    string
+	TableName string
+}
+
+// Label describes the table, e.g. "prod/eu-west-1/orders"
+func (side CompareSide) Label() string {
+	if side.Region == "" {
+		return fmt.Sprintf("%s/%s", side.Profile, side.TableName)
+	}
+	return fmt.Sprintf("%s/%s/%s", side.Profile, side.Region, side.TableName)
+}
+
+// Arguments returns the arguments selecting the region of the side, next to the command arguments
+func (side CompareSide) Arguments() []string {
+	if side.Region == "" {
+		return nil
+	}
+	return []string{"--region", side.Region}
+}
+
+// CheckCompareKeys fails when both tables do not have the same primary key, their items can not be joined
+func CheckCompareKeys(left TableDescription, right TableDescription) ([]string, error) {
+	leftKeys, rightKeys := left.TableKeyAttributes(), right.TableKeyAttributes()
+	if !slices.Equal(leftKeys, rightKeys) {
+		return nil, fmt.Errorf("%s has the key %v and %s the key %v", left.TableName, leftKeys, right.TableName, rightKeys)
+	}
+	for _, name := range leftKeys {
+		if left.AttributeType(name) != right.AttributeType(name) {
+			return nil, fmt.Errorf("key attribute %s is %s in %s and %s in %s", name, left.AttributeType(name), left.TableName, right.AttributeType(name), right.TableName)
+		}
+	}
+	return leftKeys, nil
+}
+
+// CompareDifference is an item only found in one table, or found in both with other attributes
+type CompareDifference struct {
+	Status string
+	Key    Item
+}
+
+// CompareReport is the result of a table comparison
+type CompareReport struct {
+	Left        CompareSide
+	Right       CompareSide
+	Counts      CompareCounts
+	Differences []CompareDifference // Sorted by status and key, up to the maximum differences of the comparison
+}
+
+// CompareCounts are the items read and joined so far. Until the scans end, items without a match yet are pending.
+type CompareCounts struct {
+	Scanned  [2]int
+	Pending  [2]int
+	Waiting  [2]bool // The scan of the side waits for the other side to match its pending items
+	Matching int
+	Differs  int
+	Only     [2]int
+}
+
+// TableComparison joins the items of two tables on their key while both are scanned.
+// An item is only kept as a digest until the other table returns the same key. The scan of a side waits while it has
+// maxPending items without match, so the other side catches up, and once a table is scanned the items of the other one
+// without match are final. Memory stays bounded by maxPending digests per side, and at most maxDifferences differences
+// are listed while all of them are counted.
+type TableComparison struct {
+	keyAttributes  []string
+	maxDifferences int
+	maxPending     int
+
+	lock        sync.Mutex
+	changed     *sync.Cond                      // Signalled when pending items are matched, a side is done or a scan is cancelled
+	pending     [2]map[string][sha256.Size]byte // Digest of the items by key, in DynamoDB JSON
+	done        [2]bool
+	waiting     [2]bool
+	counts      CompareCounts
+	differences []CompareDifference
+}
+
+// NewTableComparison returns a comparison joining the items on the key attributes
+func NewTableComparison(keyAttributes []string, maxDifferences int, maxPending int) *TableComparison {
+	comparison := &TableComparison{
+		keyAttributes:  keyAttributes,
+		maxDifferences: maxDifferences,
+		maxPending:     maxPending,
+		pending:        [2]map[string][sha256.Size]byte{{}, {}},
+	}
+	comparison.changed = sync.NewCond(&comparison.lock)
+	return comparison
+}
+
+// Add joins a page of items of one side, it is safe to call from the scan workers of both sides.
+// It first waits while the side has maxPending items without match, and fails when both sides do: the tables differ too much.
+func (comparison *TableComparison) Add(ctx context.Context, side int, items []Item) error {
+	comparison.lock.Lock()
+	defer comparison.lock.Unlock()
+
+	if err := comparison.waitForOtherSide(ctx, side); err != nil {
+		return err
+	}
+
+	other := 1 - side
+	for _, item := range items {
+		key, err := item.Key(comparison.keyAttributes)
+		if err != nil {
+			return err
+		}
+		keyJson, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		digest, err := itemDigest(item)
+		if err != nil {
+			return err
+		}
+
+		comparison.counts.Scanned[side]++
+		otherDigest, found := comparison.pending[other][string(keyJson)]
+		if !found {
+			if comparison.done[other] {
+				// The other table is scanned, the item is only in this one
+				comparison.addOnly(side, key)
+			} else {
+				comparison.pending[side][string(keyJson)] = digest
+			}
+			continue
+		}
+		delete(comparison.pending[other], string(keyJson))
+		if otherDigest == digest {
+			comparison.counts.Matching++
+		} else {
+			comparison.counts.Differs++
+			comparison.addDifference(CompareDifference{Status: CompareDiffers, Key: key})
+		}
+	}
+
+	// The other side may wait for its pending items to be matched
+	comparison.changed.Broadcast()
+	return nil
+}
+
+// waitForOtherSide blocks while the side has maxPending items without match and the other side is still scanned, the lock is held
+func (comparison *TableComparison) waitForOtherSide(ctx context.Context, side int) error {
+	stop := context.AfterFunc(ctx, func() {
+		comparison.lock.Lock()
+		defer comparison.lock.Unlock()
+		comparison.changed.Broadcast()
+	})
+	defer stop()
+
+	other := 1 - side
+	for len(comparison.pending[side]) >= comparison.maxPending && !comparison.done[other] {
+		if comparison.waiting[other] {
+			return fmt.Errorf("both tables have %d items without match, they differ too much to be compared", comparison.maxPending)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		comparison.waiting[side] = true
+		comparison.changed.Wait()
+		comparison.waiting[side] = false
+	}
+	return nil
+}
+
+// Done marks a side as scanned, the pending items of the other side have no match anymore
+func (comparison *TableComparison) Done(side int) error {
+	comparison.lock.Lock()
+	defer comparison.lock.Unlock()
+
+	comparison.done[side] = true
+	err := comparison.flushPending(1 - side)
+	comparison.changed.Broadcast()
+	return err
+}
+
+// flushPending counts the pending items of a side as only found in its table, sorted by key
+func (comparison *TableComparison) flushPending(side int) error {
+	keys := make([]string, 0, len(comparison.pending[side]))
+	for key := range comparison.pending[side] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, keyJson := range keys {
+		key, err := ParseItem([]byte(keyJson))
+		if err != nil {
+			return err
+		}
+		comparison.addOnly(side, key)
+	}
+	comparison.pending[side] = map[string][sha256.Size]byte{}
+	return nil
+}
+
+func (comparison *TableComparison) addOnly(side int, key Item) {
+	comparison.counts.Only[side]++
+	comparison.addDifference(CompareDifference{Status: []string{CompareOnlyLeft, CompareOnlyRight}[side], Key: key})
+}
+
+func (comparison *TableComparison) addDifference(difference CompareDifference) {
+	if len(comparison.differences) < comparison.maxDifferences {
+		comparison.differences = append(comparison.differences, difference)
+	}
+}
+
+// Counts returns the counts so far
+func (comparison *TableComparison) Counts() CompareCounts {
+	comparison.lock.Lock()
+	defer comparison.lock.Unlock()
+
+	counts := comparison.counts
+	counts.Pending = [2]int{len(comparison.pending[CompareLeft]), len(comparison.pending[CompareRight])}
+	counts.Waiting = comparison.waiting
+	return counts
+}
+
+// Finish ends the join once both scans completed, the pending items are only in their table.
+// Differences are sorted by status and key, the counts tell how many were left out of the list.
+func (comparison *TableComparison) Finish() (CompareCounts, []CompareDifference, error) {
+	comparison.lock.Lock()
+	defer comparison.lock.Unlock()
+
+	for _, side := range []int{CompareLeft, CompareRight} {
+		if err := comparison.flushPending(side); err != nil {
+			return CompareCounts{}, nil, err
+		}
+	}
+
+	statusOrder := map[string]int{CompareDiffers: 0, CompareOnlyLeft: 1, CompareOnlyRight: 2}
+	sort.SliceStable(comparison.differences, func(i, j int) bool {
+		left, right := comparison.differences[i], comparison.differences[j]
+		if left.Status != right.Status {
+			return statusOrder[left.Status] < statusOrder[right.Status]
+		}
+		leftKey, _ := json.Marshal(left.Key)
+		rightKey, _ := json.Marshal(right.Key)
+		return string(leftKey) < string(rightKey)
+	})
+	return comparison.counts, comparison.differences, nil
+}
+
+// Total returns the number of differences, listed or not
+func (counts CompareCounts) Total() int {
+	return counts.Differs + counts.Only[CompareLeft] + counts.Only[CompareRight]
+}
+
+// Summary describes the result, e.g. "120 matching, 2 differ, 1 only left, 0 only right"
+func (counts CompareCounts) Summary() string {
+	return fmt.Sprintf("%d matching, %d differ, %d only left, %d only right",
+		counts.Matching, counts.Differs, counts.Only[CompareLeft], counts.Only[CompareRight])
+}
+
+// itemDigest hashes the item in DynamoDB JSON, sets are sorted since DynamoDB does not keep their order
+func itemDigest(item Item) ([sha256.Size]byte, error) {
+	normalized := make(Item, len(item))
+	for name, value := range item {
+		normalized[name] = normalizedValue(value)
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+func normalizedValue(value AttributeValue) AttributeValue {
+	switch {
+	case value.SS != nil:
+		value.SS = slices.Sorted(slices.Values(value.SS))
+	case value.NS != nil:
+		value.NS = slices.Sorted(slices.Values(value.NS))
+	case value.BS != nil:
+		value.BS = slices.Sorted(slices.Values(value.BS))
+	case value.M != nil:
+		normalized := make(map[string]AttributeValue, len(value.M))
+		for name, element := range value.M {
+			normalized[name] = normalizedValue(element)
+		}
+		value.M = normalized
+	case value.L != nil:
+		normalized := make([]AttributeValue, len(value.L))
+		for index, element := range value.L {
+			normalized[index] = normalizedValue(element)
+		}
+		value.L = normalized
+	}
+	return value
+}
+
+// CompareRows returns the rows of the comparison report: the position of the difference, its status and its key in plain JSON
+func CompareRows(differences []CompareDifference) [][]string {
+	rows := make([][]string, len(differences))
+	for index, difference := range differences {
+		key, _ := json.Marshal(difference.Key.Plain())
+		rows[index] = []string{strconv.Itoa(index + 1), difference.Status, string(key)}
+	}
+	return rows
+}
+
+// WriteCompareReport writes the differences as JSON lines, e.g. {"Status":"Differs","Key":{"pk":"a"}}, with the key in plain JSON
+func WriteCompareReport(path string, differences []CompareDifference) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	buffer := bufio.NewWriter(file)
+	for _, difference := range differences {
+		line, err := json.Marshal(struct {
+			Status string                 `json:"Status"`
+			Key    map[string]interface{} `json:"Key"`
+		}{difference.Status, difference.Key.Plain()})
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := buffer.Write(append(line, '\n')); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return errors.Join(buffer.Flush(), file.Close())
+}
+
+// ParseGetItem decodes the output of get-item, found is false when no item has the key
+func ParseGetItem(output string) (Item, bool, error) {
+	var result struct {
+		Item Item `json:"Item"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil, false, fmt.Errorf("unexpected get-item output: %w", err)
+	}
+	return result.Item, result.Item != nil, nil
+}
@@ -0,0 +1,199 @@
+package dynamodb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseItem(t *testing.T, data string) Item {
+	t.Helper()
+	item, err := ParseItem([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing %s: %v", data, err)
+	}
+	return item
+}
+
+func TestTableComparison(t *testing.T) {
+	comparison := NewTableComparison([]string{"pk"}, 10, 10)
+
+	left := []Item{
+		mustParseItem(t, `{"pk": {"S": "same"}, "tags": {"SS": ["a", "b"]}}`),
+		mustParseItem(t, `{"pk": {"S": "changed"}, "value": {"N": "1"}}`),
+		mustParseItem(t, `{"pk": {"S": "left"}}`),
+	}
+	right := []Item{
+		mustParseItem(t, `{"pk": {"S": "changed"}, "value": {"N": "2"}}`),
+		// Sets are equal whatever the order of their elements
+		mustParseItem(t, `{"pk": {"S": "same"}, "tags": {"SS": ["b", "a"]}}`),
+		mustParseItem(t, `{"pk": {"S": "right"}}`),
+	}
+	if err := comparison.Add(context.Background(), CompareLeft, left); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts := comparison.Counts(); counts.Pending != [2]int{3, 0} || counts.Scanned[CompareLeft] != 3 {
+		t.Errorf("Unexpected counts %+v", counts)
+	}
+	if err := comparison.Add(context.Background(), CompareRight, right); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	counts, differences, err := comparison.Finish()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts.Summary() != "1 matching, 1 differ, 1 only left, 1 only right" || counts.Total() != 3 {
+		t.Errorf("Unexpected counts %+v", counts)
+	}
+
+	expected := [][]string{
+		{"1", CompareDiffers, `{"pk":"changed"}`},
+		{"2", CompareOnlyLeft, `{"pk":"left"}`},
+		{"3", CompareOnlyRight, `{"pk":"right"}`},
+	}
+	if rows := CompareRows(differences); !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+
+	if err := comparison.Add(context.Background(), CompareLeft, []Item{mustParseItem(t, `{"other": {"S": "x"}}`)}); err == nil {
+		t.Errorf("Expected an error for an item without key")
+	}
+}
+
+func TestTableComparisonMaxDifferences(t *testing.T) {
+	comparison := NewTableComparison([]string{"pk", "sk"}, 1, 10)
+	items := []Item{
+		mustParseItem(t, `{"pk": {"S": "a"}, "sk": {"N": "1"}}`),
+		mustParseItem(t, `{"pk": {"S": "a"}, "sk": {"N": "2"}}`),
+	}
+	if err := comparison.Add(context.Background(), CompareRight, items); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	counts, differences, err := comparison.Finish()
+	if err != nil || counts.Only[CompareRight] != 2 || len(differences) != 1 {
+		t.Errorf("Expected 2 differences with 1 listed, got %+v %v (%v)", counts, differences, err)
+	}
+}
+
+func TestTableComparisonWaitsForTheOtherSide(t *testing.T) {
+	comparison := NewTableComparison([]string{"pk"}, 10, 1)
+	if err := comparison.Add(context.Background(), CompareLeft, []Item{mustParseItem(t, `{"pk": {"S": "a"}}`)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The left side has 1 item without match, its next page waits until the right side matches it
+	added := make(chan error)
+	go func() {
+		added <- comparison.Add(context.Background(), CompareLeft, []Item{mustParseItem(t, `{"pk": {"S": "b"}}`)})
+	}()
+	waitUntil(t, func() bool { return comparison.Counts().Waiting[CompareLeft] })
+
+	if err := comparison.Add(context.Background(), CompareRight, []Item{mustParseItem(t, `{"pk": {"S": "a"}}`)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := <-added; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Once the right table is scanned, the left items without match are final and no longer kept
+	if err := comparison.Done(CompareRight); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := comparison.Add(context.Background(), CompareLeft, []Item{mustParseItem(t, `{"pk": {"S": "c"}}`)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts := comparison.Counts(); counts.Pending != [2]int{0, 0} || counts.Only[CompareLeft] != 2 || counts.Matching != 1 {
+		t.Errorf("Unexpected counts %+v", counts)
+	}
+}
+
+func TestTableComparisonFailsWhenBothSidesWait(t *testing.T) {
+	comparison := NewTableComparison([]string{"pk"}, 10, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comparison.Add(ctx, CompareLeft, []Item{mustParseItem(t, `{"pk": {"S": "a"}}`)})
+	added := make(chan error)
+	go func() {
+		added <- comparison.Add(ctx, CompareLeft, []Item{mustParseItem(t, `{"pk": {"S": "b"}}`)})
+	}()
+	waitUntil(t, func() bool { return comparison.Counts().Waiting[CompareLeft] })
+
+	comparison.Add(ctx, CompareRight, []Item{mustParseItem(t, `{"pk": {"S": "x"}}`)})
+	if err := comparison.Add(ctx, CompareRight, []Item{mustParseItem(t, `{"pk": {"S": "y"}}`)}); err == nil || !strings.Contains(err.Error(), "differ too much") {
+		t.Errorf("Expected an error when both sides wait, got %v", err)
+	}
+
+	// Cancelling wakes up the waiting side
+	cancel()
+	if err := <-added; err != context.Canceled {
+		t.Errorf("Expected the wait to be cancelled, got %v", err)
+	}
+}
+
+func waitUntil(t *testing.T, condition func() bool) {
+	t.Helper()
+	for start := time.Now(); !condition(); time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("Condition not met")
+		}
+	}
+}
+
+func TestCheckCompareKeys(t *testing.T) {
+	table := func(name string, sortKeyType string) TableDescription {
+		return TableDescription{
+			TableName:            name,
+			KeySchema:            []KeySchemaElement{{AttributeName: "sk", KeyType: KeyTypeRange}, {AttributeName: "pk", KeyType: KeyTypeHash}},
+			AttributeDefinitions: []AttributeDefinition{{AttributeName: "pk", AttributeType: "S"}, {AttributeName: "sk", AttributeType: sortKeyType}},
+		}
+	}
+
+	keys, err := CheckCompareKeys(table("staging", "N"), table("prod", "N"))
+	if err != nil || !reflect.DeepEqual(keys, []string{"pk", "sk"}) {
+		t.Errorf("Unexpected keys %v (%v)", keys, err)
+	}
+	if _, err := CheckCompareKeys(table("staging", "N"), table("prod", "S")); err == nil || !strings.Contains(err.Error(), "key attribute sk") {
+		t.Errorf("Expected a key type error, got %v", err)
+	}
+	other := table("prod", "N")
+	other.KeySchema = other.KeySchema[1:]
+	if _, err := CheckCompareKeys(table("staging", "N"), other); err == nil {
+		t.Errorf("Expected a key schema error")
+	}
+}
+
+func TestCompareSideAndReport(t *testing.T) {
+	side := CompareSide{Profile: "prod", Region: "eu-west-1", TableName: "orders"}
+	if side.Label() != "prod/eu-west-1/orders" || !reflect.DeepEqual(side.Arguments(), []string{"--region", "eu-west-1"}) {
+		t.Errorf("Unexpected side %q %v", side.Label(), side.Arguments())
+	}
+	side.Region = ""
+	if side.Label() != "prod/orders" || side.Arguments() != nil {
+		t.Errorf("Unexpected side %q %v", side.Label(), side.Arguments())
+	}
+
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+	differences := []CompareDifference{{Status: CompareDiffers, Key: mustParseItem(t, `{"pk": {"N": "7"}}`)}}
+	if err := WriteCompareReport(path, differences); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "{\"Status\":\"Differs\",\"Key\":{\"pk\":7}}\n" {
+		t.Errorf("Unexpected report %q", data)
+	}
+
+	item, found, err := ParseGetItem(`{"Item": {"pk": {"S": "a"}}}`)
+	if err != nil || !found || *item["pk"].S != "a" {
+		t.Errorf("Unexpected item %v %v (%v)", item, found, err)
+	}
+	if _, found, err := ParseGetItem(`{}`); err != nil || found {
+		t.Errorf("Expected no item, got %v (%v)", found, err)
+	}
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CountTotals sums the pages of a scan or query run with --select COUNT
+type CountTotals struct {
+	Count            int
+	ScannedCount     int
+	Pages            int
+	ConsumedCapacity float64
+}
+
+// CountItems follows LastEvaluatedKey from the first page to the last, adding up the counts of every page.
+// runPage runs the scan or query from startKey, empty for the first page. onPage receives the totals after each page.
+// A cancelled ctx returns the totals counted so far with ctx.Err().
+func CountItems(ctx context.Context, runPage func(ctx context.Context, startKey string) (string, error), onPage func(totals CountTotals) error) (CountTotals, error) {
+	var totals CountTotals
+	startKey := ""
+	for {
+		output, err := runPage(ctx, startKey)
+		if ctx.Err() != nil {
+			return totals, ctx.Err()
+		}
+		if err != nil {
+			return totals, err
+		}
+		page, err := ParseScanPage(output)
+		if err != nil {
+			return totals, err
+		}
+
+		totals.Count += page.Count
+		totals.ScannedCount += page.ScannedCount
+		totals.Pages++
+		totals.ConsumedCapacity += page.ConsumedCapacity
+		if err := onPage(totals); err != nil {
+			return totals, err
+		}
+
+		if len(page.LastEvaluatedKey) == 0 {
+			return totals, nil
+		}
+		key, err := json.Marshal(page.LastEvaluatedKey)
+		if err != nil {
+			return totals, err
+		}
+		startKey = string(key)
+	}
+}
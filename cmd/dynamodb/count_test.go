@@ -0,0 +1,66 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeCountPages returns a count page runner with three pages, each continuing from the key of the previous one
+func fakeCountPages(startKeys *[]string) func(ctx context.Context, startKey string) (string, error) {
+	return func(ctx context.Context, startKey string) (string, error) {
+		*startKeys = append(*startKeys, startKey)
+		switch startKey {
+		case "":
+			return `{"Count": 2, "ScannedCount": 10, "LastEvaluatedKey": {"pk": {"S": "a"}}, "ConsumedCapacity": {"CapacityUnits": 1.5}}`, nil
+		case `{"pk":{"S":"a"}}`:
+			return `{"Count": 0, "ScannedCount": 10, "LastEvaluatedKey": {"pk": {"S": "b"}}, "ConsumedCapacity": {"CapacityUnits": 1.5}}`, nil
+		default:
+			return `{"Count": 3, "ScannedCount": 4, "ConsumedCapacity": {"CapacityUnits": 0.5}}`, nil
+		}
+	}
+}
+
+func TestCountItems(t *testing.T) {
+	var startKeys []string
+	var progress []int
+	totals, err := CountItems(context.Background(), fakeCountPages(&startKeys), func(totals CountTotals) error {
+		progress = append(progress, totals.Count)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := CountTotals{Count: 5, ScannedCount: 24, Pages: 3, ConsumedCapacity: 3.5}
+	if totals != expected {
+		t.Errorf("Got %+v, expected %+v", totals, expected)
+	}
+	if fmt.Sprint(progress) != "[2 2 5]" {
+		t.Errorf("Unexpected running totals %v", progress)
+	}
+	if fmt.Sprint(startKeys) != `[ {"pk":{"S":"a"}} {"pk":{"S":"b"}}]` {
+		t.Errorf("Unexpected start keys %v", startKeys)
+	}
+}
+
+func TestCountItemsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var startKeys []string
+	totals, err := CountItems(ctx, fakeCountPages(&startKeys), func(totals CountTotals) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled || totals.Count != 2 || totals.Pages != 1 {
+		t.Errorf("Expected the first page only after cancelling, got %+v (%v)", totals, err)
+	}
+}
+
+func TestCountItemsStopsOnFailure(t *testing.T) {
+	runPage := func(ctx context.Context, startKey string) (string, error) {
+		return "", fmt.Errorf("ValidationException")
+	}
+	if _, err := CountItems(context.Background(), runPage, func(CountTotals) error { return nil }); err == nil {
+		t.Error("Expected the page failure")
+	}
+}
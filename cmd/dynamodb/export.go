@@ -0,0 +1,273 @@
+package dynamodb
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Formats of a table export
+const (
+	ExportJsonLinesDynamoDB = "JSON lines (DynamoDB JSON)"
+	ExportJsonLinesPlain    = "JSON lines (plain JSON)"
+	ExportCsv               = "CSV"
+)
+
+var ExportFormats = []string{ExportJsonLinesDynamoDB, ExportJsonLinesPlain, ExportCsv}
+
+// ScanPage is one page of a scan or query output
+type ScanPage struct {
+	Items            []Item
+	Count            int // Items matching the filter, the only result of --select COUNT
+	ScannedCount     int // Items read before the filter
+	LastEvaluatedKey Item
+	ConsumedCapacity float64 // Capacity units, set when the scan ran with --return-consumed-capacity
+}
+
+// ParseScanPage decodes the output of a scan or query run without automatic pagination
+func ParseScanPage(output string) (ScanPage, error) {
+	var result struct {
+		Items            []Item `json:"Items"`
+		Count            int    `json:"Count"`
+		ScannedCount     int    `json:"ScannedCount"`
+		LastEvaluatedKey Item   `json:"LastEvaluatedKey"`
+		ConsumedCapacity *struct {
+			CapacityUnits float64 `json:"CapacityUnits"`
+		} `json:"ConsumedCapacity"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return ScanPage{}, fmt.Errorf("unexpected scan output: %w", err)
+	}
+
+	page := ScanPage{Items: result.Items, Count: result.Count, ScannedCount: result.ScannedCount, LastEvaluatedKey: result.LastEvaluatedKey}
+	if result.ConsumedCapacity != nil {
+		page.ConsumedCapacity = result.ConsumedCapacity.CapacityUnits
+	}
+	return page, nil
+}
+
+// ParallelScan scans every segment in its own worker, each following its LastEvaluatedKey until the segment ends.
+// runPage runs the scan of a segment from startKey, empty for the first page. onPage receives the pages one at a time.
+// The first failure stops every worker, ctx cancels them.
+func ParallelScan(ctx context.Context, segments int, runPage func(ctx context.Context, segment int, startKey string) (string, error), onPage func(segment int, page ScanPage) error) error {
+	if segments < 1 {
+		return fmt.Errorf("segments must be at least 1")
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var workers sync.WaitGroup
+	var firstError error
+
+	fail := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if firstError == nil {
+			firstError = err
+		}
+		cancel()
+	}
+
+	for segment := 0; segment < segments; segment++ {
+		workers.Add(1)
+		go func(segment int) {
+			defer workers.Done()
+
+			startKey := ""
+			for workerCtx.Err() == nil {
+				output, err := runPage(workerCtx, segment, startKey)
+				if workerCtx.Err() != nil {
+					return
+				}
+				if err != nil {
+					fail(fmt.Errorf("segment %d: %w", segment, err))
+					return
+				}
+				page, err := ParseScanPage(output)
+				if err != nil {
+					fail(fmt.Errorf("segment %d: %w", segment, err))
+					return
+				}
+
+				lock.Lock()
+				err = onPage(segment, page)
+				lock.Unlock()
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				if len(page.LastEvaluatedKey) == 0 {
+					return
+				}
+				key, err := json.Marshal(page.LastEvaluatedKey)
+				if err != nil {
+					fail(err)
+					return
+				}
+				startKey = string(key)
+			}
+		}(segment)
+	}
+
+	workers.Wait()
+	if firstError != nil {
+		return firstError
+	}
+	return ctx.Err()
+}
+
+// ExportWriter writes exported items to a file. Every Write is flushed, so closing a cancelled export keeps a valid file with the items written so far.
+type ExportWriter struct {
+	format     string
+	path       string
+	keyColumns []string
+	file       *os.File
+	buffer     *bufio.Writer
+
+	// CSV exports stage the items as DynamoDB JSON lines, the header (the union of every attribute) is known on Close
+	columns    []string
+	seen       map[string]bool
+	stagedPath string
+}
+
+// NewExportWriter creates the export file. CSV exports start with the key columns.
+func NewExportWriter(path string, format string, keyColumns []string) (*ExportWriter, error) {
+	writer := &ExportWriter{format: format, path: path, keyColumns: keyColumns}
+
+	if format == ExportCsv {
+		staged, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+		if err != nil {
+			return nil, err
+		}
+		writer.file = staged
+		writer.stagedPath = staged.Name()
+		writer.seen = make(map[string]bool)
+	} else {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		writer.file = file
+	}
+	writer.buffer = bufio.NewWriter(writer.file)
+	return writer, nil
+}
+
+// Write appends the items, one JSON line each
+func (writer *ExportWriter) Write(items []Item) error {
+	for _, item := range items {
+		var data interface{} = item
+		if writer.format == ExportJsonLinesPlain {
+			data = item.Plain()
+		}
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.buffer.Write(append(line, '\n')); err != nil {
+			return err
+		}
+
+		if writer.seen != nil {
+			for _, name := range item.names() {
+				if !writer.seen[name] {
+					writer.seen[name] = true
+					writer.columns = append(writer.columns, name)
+				}
+			}
+		}
+	}
+	return writer.buffer.Flush()
+}
+
+// Close completes the file, a CSV export is written from the staged items here
+func (writer *ExportWriter) Close() error {
+	flushErr := writer.buffer.Flush()
+	closeErr := writer.file.Close()
+	if writer.format != ExportCsv {
+		return errors.Join(flushErr, closeErr)
+	}
+
+	defer os.Remove(writer.stagedPath)
+	if err := errors.Join(flushErr, closeErr); err != nil {
+		return err
+	}
+	return writer.writeCsv()
+}
+
+func (writer *ExportWriter) writeCsv() error {
+	// Key columns first, then the other attributes by name
+	var header []string
+	for _, column := range writer.keyColumns {
+		if writer.seen[column] {
+			header = append(header, column)
+		}
+	}
+	var others []string
+	for _, column := range writer.columns {
+		if !slices.Contains(header, column) {
+			others = append(others, column)
+		}
+	}
+	sort.Strings(others)
+	header = append(header, others...)
+
+	staged, err := os.Open(writer.stagedPath)
+	if err != nil {
+		return err
+	}
+	defer staged.Close()
+
+	file, err := os.Create(writer.path)
+	if err != nil {
+		return err
+	}
+	csvWriter := csv.NewWriter(file)
+	if err := csvWriter.Write(header); err != nil {
+		file.Close()
+		return err
+	}
+
+	scanner := bufio.NewScanner(staged)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Items are up to 400 KB, as JSON they can be larger
+	for scanner.Scan() {
+		item, err := ParseItem(scanner.Bytes())
+		if err != nil {
+			file.Close()
+			return err
+		}
+		record := make([]string, len(header))
+		for index, column := range header {
+			if value, exists := item[column]; exists {
+				record[index] = csvValue(value)
+			}
+		}
+		if err := csvWriter.Write(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return errors.Join(scanner.Err(), csvWriter.Error(), file.Close())
+}
+
+// csvValue renders strings as-is and every other value as compact plain JSON
+func csvValue(value AttributeValue) string {
+	plain := value.Plain()
+	if text, ok := plain.(string); ok {
+		return text
+	}
+	text, _ := json.Marshal(plain)
+	return string(text)
+}
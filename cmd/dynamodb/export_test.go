@@ -0,0 +1,114 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeSegmentPages returns a scan page runner where every segment has two pages of one item
+func fakeSegmentPages() func(ctx context.Context, segment int, startKey string) (string, error) {
+	return func(ctx context.Context, segment int, startKey string) (string, error) {
+		if startKey == "" {
+			return fmt.Sprintf(`{"Items": [{"id": {"S": "%d-a"}}], "LastEvaluatedKey": {"id": {"S": "%d-a"}}, "ConsumedCapacity": {"CapacityUnits": 0.5}}`, segment, segment), nil
+		}
+		return fmt.Sprintf(`{"Items": [{"id": {"S": "%d-b"}, "total": {"N": "2"}}], "ConsumedCapacity": {"CapacityUnits": 0.5}}`, segment), nil
+	}
+}
+
+func TestParallelScan(t *testing.T) {
+	var ids []string
+	capacity := 0.0
+	err := ParallelScan(context.Background(), 3, fakeSegmentPages(), func(segment int, page ScanPage) error {
+		for _, item := range page.Items {
+			ids = append(ids, *item["id"].S)
+		}
+		capacity += page.ConsumedCapacity
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sort.Strings(ids)
+	expected := "0-a 0-b 1-a 1-b 2-a 2-b"
+	if strings.Join(ids, " ") != expected {
+		t.Errorf("Got %v, expected %s", ids, expected)
+	}
+	if capacity != 3 {
+		t.Errorf("Expected 3 capacity units, got %v", capacity)
+	}
+}
+
+func TestParallelScanStopsOnFailure(t *testing.T) {
+	runPage := func(ctx context.Context, segment int, startKey string) (string, error) {
+		if segment == 1 {
+			return "", fmt.Errorf("AccessDeniedException")
+		}
+		return fakeSegmentPages()(ctx, segment, startKey)
+	}
+
+	err := ParallelScan(context.Background(), 2, runPage, func(segment int, page ScanPage) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "segment 1: AccessDeniedException") {
+		t.Errorf("Expected the segment failure, got %v", err)
+	}
+}
+
+func TestParallelScanCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pages := 0
+	err := ParallelScan(ctx, 1, fakeSegmentPages(), func(segment int, page ScanPage) error {
+		pages++
+		cancel()
+		return nil
+	})
+	if err != context.Canceled || pages != 1 {
+		t.Errorf("Expected a cancelled scan after one page, got %v after %d pages", err, pages)
+	}
+}
+
+func TestExportWriterFormats(t *testing.T) {
+	first, _ := ParseItem([]byte(`{"name": {"S": "Ada, \"the first\""}, "id": {"N": "1"}}`))
+	second, _ := ParseItem([]byte(`{"id": {"N": "2"}, "tags": {"SS": ["a"]}, "age": {"N": "36"}}`))
+
+	expected := map[string]string{
+		ExportJsonLinesDynamoDB: `{"id":{"N":"1"},"name":{"S":"Ada, \"the first\""}}` + "\n" +
+			`{"age":{"N":"36"},"id":{"N":"2"},"tags":{"SS":["a"]}}` + "\n",
+		ExportJsonLinesPlain: `{"id":1,"name":"Ada, \"the first\""}` + "\n" +
+			`{"age":36,"id":2,"tags":["a"]}` + "\n",
+		ExportCsv: "id,age,name,tags\n" +
+			`1,,"Ada, ""the first""",` + "\n" +
+			`2,36,,"[""a""]"` + "\n",
+	}
+
+	for format, content := range expected {
+		path := filepath.Join(t.TempDir(), "export")
+		writer, err := NewExportWriter(path, format, []string{"id"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if err := writer.Write([]Item{first}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if err := writer.Write([]Item{second}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+
+		written, _ := os.ReadFile(path)
+		if string(written) != content {
+			t.Errorf("%s: got\n%s\nexpected\n%s", format, written, content)
+		}
+
+		// Only the export is left, CSV staging files are removed
+		if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+			t.Errorf("%s: expected only the export file, got %d files", format, len(files))
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Filter operators supported by the filter builder
+const (
+	FilterEqual              = "="
+	FilterNotEqual           = "<>"
+	FilterLess               = "<"
+	FilterLessOrEqual        = "<="
+	FilterGreater            = ">"
+	FilterGreaterOrEqual     = ">="
+	FilterBeginsWith         = "begins_with"
+	FilterContains           = "contains"
+	FilterAttributeExists    = "attribute_exists"
+	FilterAttributeNotExists = "attribute_not_exists"
+	FilterIn                 = "IN"
+	FilterSize               = "size"
+)
+
+var FilterOperators = []string{
+	FilterEqual, FilterNotEqual, FilterLess, FilterLessOrEqual, FilterGreater, FilterGreaterOrEqual,
+	FilterBeginsWith, FilterContains, FilterAttributeExists, FilterAttributeNotExists, FilterIn, FilterSize,
+}
+
+// FilterValueTypes are the value types a filter condition can compare with
+var FilterValueTypes = []string{"S", "N", "BOOL", "NULL", "B"}
+
+var comparators = []string{FilterNotEqual, FilterLessOrEqual, FilterGreaterOrEqual, FilterEqual, FilterLess, FilterGreater}
+
+// FilterCondition is a row of the filter builder
+type FilterCondition struct {
+	Attribute string // Attribute name or dotted path (e.g. address.city)
+	Operator  string // One of FilterOperators
+	Type      string // One of FilterValueTypes
+	Value     string // Comma separated values for IN, optional comparator prefix for size (e.g. "> 3")
+}
+
+// Filter is the filter state of a scan or query
+type Filter struct {
+	Conditions    []FilterCondition
+	RawExpression string // Expression written by hand, combined with the conditions using AND
+	RawNames      string // JSON object of the expression attribute names used by RawExpression
+	RawValues     string // JSON object of the expression attribute values used by RawExpression
+}
+
+// Expression is a compiled DynamoDB expression with its placeholders
+type Expression struct {
+	Expression string
+	Names      map[string]string
+	Values     map[string]AttributeValue
+}
+
+// IsEmpty reports whether the filter has neither conditions nor raw expression
+func (filter Filter) IsEmpty() bool {
+	return len(filter.Conditions) == 0 && strings.TrimSpace(filter.RawExpression) == ""
+}
+
+// Build compiles the filter to a --filter-expression. Conditions get generated #f<n> names and :f<n> values.
+func (filter Filter) Build() (Expression, error) {
+	expression := Expression{Names: map[string]string{}, Values: map[string]AttributeValue{}}
+	var parts []string
+
+	for index, condition := range filter.Conditions {
+		part, err := condition.build(index, &expression)
+		if err != nil {
+			return Expression{}, fmt.Errorf("condition %d: %w", index+1, err)
+		}
+		parts = append(parts, part)
+	}
+
+	if raw := strings.TrimSpace(filter.RawExpression); raw != "" {
+		if err := mergeRawJson(filter.RawNames, &expression.Names); err != nil {
+			return Expression{}, fmt.Errorf("raw attribute names: %w", err)
+		}
+		if err := mergeRawJson(filter.RawValues, &expression.Values); err != nil {
+			return Expression{}, fmt.Errorf("raw attribute values: %w", err)
+		}
+		if len(parts) > 0 {
+			raw = fmt.Sprintf("(%s)", raw)
+		}
+		parts = append(parts, raw)
+	}
+
+	expression.Expression = strings.Join(parts, " AND ")
+	return expression, nil
+}
+
+// Arguments returns the AWS CLI arguments of the filter, to be merged with the key condition placeholders
+func (expression Expression) Arguments() []string {
+	if expression.Expression == "" {
+		return nil
+	}
+	return []string{"--filter-expression", expression.Expression}
+}
+
+func (condition FilterCondition) build(index int, expression *Expression) (string, error) {
+	if strings.TrimSpace(condition.Attribute) == "" {
+		return "", fmt.Errorf("attribute name is required")
+	}
+
+	// Every segment of a dotted path gets its own name placeholder, so reserved words are always safe
+	var nameParts []string
+	for segment, name := range strings.Split(condition.Attribute, ".") {
+		placeholder := fmt.Sprintf("#f%d", index)
+		if segment > 0 {
+			placeholder = fmt.Sprintf("#f%d_%d", index, segment)
+		}
+		expression.Names[placeholder] = name
+		nameParts = append(nameParts, placeholder)
+	}
+	name := strings.Join(nameParts, ".")
+	valuePlaceholder := fmt.Sprintf(":f%d", index)
+
+	switch condition.Operator {
+	case FilterAttributeExists, FilterAttributeNotExists:
+		return fmt.Sprintf("%s(%s)", condition.Operator, name), nil
+
+	case FilterBeginsWith, FilterContains:
+		value, err := NewAttributeValue(condition.Type, condition.Value)
+		if err != nil {
+			return "", err
+		}
+		expression.Values[valuePlaceholder] = value
+		return fmt.Sprintf("%s(%s, %s)", condition.Operator, name, valuePlaceholder), nil
+
+	case FilterIn:
+		var placeholders []string
+		for valueIndex, item := range strings.Split(condition.Value, ",") {
+			value, err := NewAttributeValue(condition.Type, strings.TrimSpace(item))
+			if err != nil {
+				return "", err
+			}
+			placeholder := fmt.Sprintf("%s_%d", valuePlaceholder, valueIndex)
+			expression.Values[placeholder] = value
+			placeholders = append(placeholders, placeholder)
+		}
+		return fmt.Sprintf("%s IN (%s)", name, strings.Join(placeholders, ", ")), nil
+
+	case FilterSize:
+		comparator, size := splitComparator(condition.Value)
+		value, err := NewAttributeValue("N", size)
+		if err != nil {
+			return "", err
+		}
+		expression.Values[valuePlaceholder] = value
+		return fmt.Sprintf("size(%s) %s %s", name, comparator, valuePlaceholder), nil
+
+	case FilterEqual, FilterNotEqual, FilterLess, FilterLessOrEqual, FilterGreater, FilterGreaterOrEqual:
+		value, err := NewAttributeValue(condition.Type, condition.Value)
+		if err != nil {
+			return "", err
+		}
+		expression.Values[valuePlaceholder] = value
+		return fmt.Sprintf("%s %s %s", name, condition.Operator, valuePlaceholder), nil
+	}
+
+	return "", fmt.Errorf("unsupported operator %q", condition.Operator)
+}
+
+// splitComparator splits an optional comparator prefix (e.g. ">= 3") from a value, defaulting to "="
+func splitComparator(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	for _, comparator := range comparators {
+		if strings.HasPrefix(value, comparator) {
+			return comparator, strings.TrimSpace(strings.TrimPrefix(value, comparator))
+		}
+	}
+	return FilterEqual, value
+}
+
+// mergeRawJson decodes a JSON object typed by the user into target, failing when a key is already a generated placeholder
+func mergeRawJson[T any](raw string, target *map[string]T) error {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var decoded map[string]T
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return err
+	}
+	for key := range decoded {
+		if _, exists := (*target)[key]; exists {
+			return fmt.Errorf("%s is already used by a filter condition, choose another placeholder", key)
+		}
+	}
+	for key, value := range decoded {
+		(*target)[key] = value
+	}
+	return nil
+}
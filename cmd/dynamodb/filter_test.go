@@ -0,0 +1,82 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFilterBuild(t *testing.T) {
+	filter := Filter{Conditions: []FilterCondition{
+		{Attribute: "status", Operator: FilterEqual, Type: "S", Value: "active"},
+		{Attribute: "address.city", Operator: FilterBeginsWith, Type: "S", Value: "Ber"},
+		{Attribute: "deletedAt", Operator: FilterAttributeNotExists},
+		{Attribute: "tier", Operator: FilterIn, Type: "N", Value: "1, 2"},
+		{Attribute: "tags", Operator: FilterSize, Value: ">= 3"},
+	}}
+
+	expression, err := filter.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "#f0 = :f0 AND begins_with(#f1.#f1_1, :f1) AND attribute_not_exists(#f2) AND #f3 IN (:f3_0, :f3_1) AND size(#f4) >= :f4"
+	if expression.Expression != expected {
+		t.Errorf("Got expression %q, expected %q", expression.Expression, expected)
+	}
+
+	expectedNames := map[string]string{"#f0": "status", "#f1": "address", "#f1_1": "city", "#f2": "deletedAt", "#f3": "tier", "#f4": "tags"}
+	if !reflect.DeepEqual(expression.Names, expectedNames) {
+		t.Errorf("Got names %v, expected %v", expression.Names, expectedNames)
+	}
+
+	values, _ := json.Marshal(expression.Values)
+	expectedValues := `{":f0":{"S":"active"},":f1":{"S":"Ber"},":f3_0":{"N":"1"},":f3_1":{"N":"2"},":f4":{"N":"3"}}`
+	if string(values) != expectedValues {
+		t.Errorf("Got values %s, expected %s", values, expectedValues)
+	}
+}
+
+func TestFilterBuildWithRawExpression(t *testing.T) {
+	filter := Filter{
+		Conditions:    []FilterCondition{{Attribute: "active", Operator: FilterEqual, Type: "BOOL", Value: "true"}},
+		RawExpression: "#n > :min OR attribute_exists(legacy)",
+		RawNames:      `{"#n": "count"}`,
+		RawValues:     `{":min": {"N": "10"}}`,
+	}
+
+	expression, err := filter.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expression.Expression != "#f0 = :f0 AND (#n > :min OR attribute_exists(legacy))" {
+		t.Errorf("Unexpected expression %q", expression.Expression)
+	}
+	if expression.Names["#n"] != "count" || expression.Values[":min"].N == nil {
+		t.Errorf("Expected raw placeholders to be merged, got %v %v", expression.Names, expression.Values)
+	}
+}
+
+func TestFilterBuildErrors(t *testing.T) {
+	filters := map[string]Filter{
+		"missing attribute": {Conditions: []FilterCondition{{Operator: FilterEqual, Type: "S", Value: "x"}}},
+		"invalid bool":      {Conditions: []FilterCondition{{Attribute: "a", Operator: FilterEqual, Type: "BOOL", Value: "yes"}}},
+		"unknown operator":  {Conditions: []FilterCondition{{Attribute: "a", Operator: "LIKE", Type: "S", Value: "x"}}},
+		"invalid raw json":  {RawExpression: "a = :a", RawValues: `{":a": `},
+		"raw name clash": {
+			Conditions:    []FilterCondition{{Attribute: "a", Operator: FilterEqual, Type: "S", Value: "x"}},
+			RawExpression: "#f0 = :v", RawNames: `{"#f0": "b"}`, RawValues: `{":v": {"S": "y"}}`,
+		},
+		"raw value clash": {
+			Conditions:    []FilterCondition{{Attribute: "a", Operator: FilterEqual, Type: "S", Value: "x"}},
+			RawExpression: "b = :f0", RawValues: `{":f0": {"S": "y"}}`,
+		},
+	}
+
+	for name, filter := range filters {
+		if _, err := filter.Build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package dynamodb
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BatchWriteLimit is the most put requests a batch-write-item call accepts
+const BatchWriteLimit = 25
+
+// ImportError is an invalid line of an import file
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+func (importError ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", importError.Line, importError.Err)
+}
+
+// csvTypedColumn matches a CSV header with a type suffix, the format of the table headers (e.g. "age (N)")
+var csvTypedColumn = regexp.MustCompile(`^(.+?)\s*\((S|N|B|BOOL|NULL|SS|NS|BS|M|L)\)$`)
+
+// ReadImportItems reads the items of an import file in one of the export formats, checking every line.
+// Items must hold the table key attributes, and key attributes of the table and its indexes must have their defined type.
+// Invalid lines are returned as ImportError, the error is only set when the file can not be read.
+func ReadImportItems(reader io.Reader, format string, table TableDescription) ([]Item, []ImportError, error) {
+	var items []Item
+	var lines []int
+	var invalid []ImportError
+
+	add := func(line int, item Item, err error) {
+		if err == nil {
+			err = checkImportKeys(item, table)
+		}
+		if err != nil {
+			invalid = append(invalid, ImportError{Line: line, Err: err})
+			return
+		}
+		items = append(items, item)
+		lines = append(lines, line)
+	}
+
+	var err error
+	if format == ExportCsv {
+		err = readCsvItems(reader, table, add)
+	} else {
+		err = readJsonLinesItems(reader, format, add)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Items with the same key overwrite each other, a batch even fails on them
+	keyAttributes := table.TableKeyAttributes()
+	firstLines := make(map[string]int)
+	var unique []Item
+	for index, item := range items {
+		key, _ := item.Key(keyAttributes)
+		keyJson, _ := json.Marshal(key)
+		if firstLine, exists := firstLines[string(keyJson)]; exists {
+			invalid = append(invalid, ImportError{Line: lines[index], Err: fmt.Errorf("same key as line %d", firstLine)})
+			continue
+		}
+		firstLines[string(keyJson)] = lines[index]
+		unique = append(unique, item)
+	}
+	sort.SliceStable(invalid, func(i, j int) bool { return invalid[i].Line < invalid[j].Line })
+	return unique, invalid, nil
+}
+
+func readJsonLinesItems(reader io.Reader, format string, add func(line int, item Item, err error)) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Items are up to 400 KB, as JSON they can be larger
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := strings.TrimSpace(scanner.Text())
+		if data == "" {
+			continue
+		}
+		if format == ExportJsonLinesPlain {
+			item, err := ParsePlainItem([]byte(data), nil)
+			add(line, item, err)
+		} else {
+			item, err := ParseItem([]byte(data))
+			add(line, item, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func readCsvItems(reader io.Reader, table TableDescription, add func(line int, item Item, err error)) error {
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		return fmt.Errorf("unable to read the CSV header: %w", err)
+	}
+
+	names := make([]string, len(header))
+	types := make([]string, len(header))
+	for index, column := range header {
+		names[index] = strings.TrimSpace(column)
+		if match := csvTypedColumn.FindStringSubmatch(names[index]); match != nil {
+			names[index], types[index] = match[1], match[2]
+		} else {
+			// Key attributes have the type of their definition, other columns are guessed from the value
+			types[index] = table.AttributeType(names[index])
+		}
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			parseError, isParseError := err.(*csv.ParseError)
+			if !isParseError {
+				return err
+			}
+			add(parseError.StartLine, nil, parseError.Err)
+			continue
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		item := Item{}
+		var itemErr error
+		for index, cell := range record {
+			if cell == "" {
+				continue
+			}
+			value, err := csvAttributeValue(cell, types[index])
+			if err != nil {
+				itemErr = fmt.Errorf("%s: %w", names[index], err)
+				break
+			}
+			item[names[index]] = value
+		}
+		add(line, item, itemErr)
+	}
+}
+
+// csvAttributeValue converts a CSV cell to a value of the given type. Without type, cells holding JSON other than a string
+// (numbers, booleans, null, lists and objects, as exported) are converted like plain JSON, the others are strings.
+func csvAttributeValue(cell string, attrType string) (AttributeValue, error) {
+	switch attrType {
+	case "S", "N", "B", "BOOL", "NULL":
+		return NewAttributeValue(attrType, cell)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(cell))
+	decoder.UseNumber()
+	var plain interface{}
+	if err := decoder.Decode(&plain); err != nil || decoder.More() {
+		if attrType != "" {
+			return AttributeValue{}, fmt.Errorf("%s values must be JSON, got %q", attrType, cell)
+		}
+		return AttributeValue{S: &cell}, nil
+	}
+	if _, isString := plain.(string); isString && attrType == "" {
+		return AttributeValue{S: &cell}, nil
+	}
+
+	var hint *AttributeValue
+	switch attrType {
+	case "SS":
+		hint = &AttributeValue{SS: []string{}}
+	case "NS":
+		hint = &AttributeValue{NS: []string{}}
+	case "BS":
+		hint = &AttributeValue{BS: []string{}}
+	}
+	value, err := fromPlain(plain, hint)
+	if err != nil {
+		return AttributeValue{}, err
+	}
+	if attrType != "" && value.Type() != attrType {
+		return AttributeValue{}, fmt.Errorf("expected a %s value, got %q", attrType, cell)
+	}
+	return value, value.Validate()
+}
+
+// checkImportKeys checks the item holds the table key, and that the key attributes of the table and its indexes have their type
+func checkImportKeys(item Item, table TableDescription) error {
+	if _, err := item.Key(table.TableKeyAttributes()); err != nil {
+		return err
+	}
+	for _, name := range table.KeyAttributes() {
+		value, exists := item[name]
+		if !exists {
+			continue
+		}
+		if expected := table.AttributeType(name); value.Type() != expected {
+			return fmt.Errorf("key attribute %s must be %s, got %s", name, expected, value.Type())
+		}
+	}
+	return nil
+}
+
+// RejectedItem is an item the import could not write
+type RejectedItem struct {
+	Item Item
+	Err  error
+}
+
+// BatchWriter writes items with batch-write-item, retrying the UnprocessedItems with an exponential backoff
+type BatchWriter struct {
+	TableName   string
+	Run         func(ctx context.Context, requestItems string) (string, error) // Runs batch-write-item with the --request-items JSON
+	MaxAttempts int                                                            // Attempts of a batch, its unprocessed items are rejected afterwards
+	Backoff     time.Duration                                                  // Delay before the first retry, doubled on every retry
+}
+
+// Write writes the items in batches of BatchWriteLimit, onBatch receives the number of items written and the items rejected
+// by every batch. A failed batch rejects its items and the import goes on, ctx cancels it.
+func (writer BatchWriter) Write(ctx context.Context, items []Item, onBatch func(written int, rejected []RejectedItem) error) error {
+	for start := 0; start < len(items); start += BatchWriteLimit {
+		end := min(start+BatchWriteLimit, len(items))
+		written, rejected := writer.writeBatch(ctx, items[start:end])
+		if err := onBatch(written, rejected); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer BatchWriter) writeBatch(ctx context.Context, pending []Item) (int, []RejectedItem) {
+	written := 0
+	reject := func(items []Item, err error) []RejectedItem {
+		rejected := make([]RejectedItem, len(items))
+		for index, item := range items {
+			rejected[index] = RejectedItem{Item: item, Err: err}
+		}
+		return rejected
+	}
+
+	for attempt := 1; ; attempt++ {
+		type putRequest struct {
+			PutRequest struct {
+				Item Item `json:"Item"`
+			} `json:"PutRequest"`
+		}
+		requests := make([]putRequest, len(pending))
+		for index, item := range pending {
+			requests[index].PutRequest.Item = item
+		}
+		requestItems, err := json.Marshal(map[string][]putRequest{writer.TableName: requests})
+		if err != nil {
+			return written, reject(pending, err)
+		}
+
+		output, err := writer.Run(ctx, string(requestItems))
+		if ctx.Err() != nil {
+			return written, nil
+		}
+		if err != nil {
+			return written, reject(pending, err)
+		}
+
+		var result struct {
+			UnprocessedItems map[string][]putRequest `json:"UnprocessedItems"`
+		}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			return written, reject(pending, fmt.Errorf("unexpected batch-write-item output: %w", err))
+		}
+		unprocessed := result.UnprocessedItems[writer.TableName]
+		written += len(pending) - len(unprocessed)
+		if len(unprocessed) == 0 {
+			return written, nil
+		}
+
+		pending = make([]Item, len(unprocessed))
+		for index, request := range unprocessed {
+			pending[index] = request.PutRequest.Item
+		}
+		if attempt >= writer.MaxAttempts {
+			return written, reject(pending, fmt.Errorf("still unprocessed after %d attempts", attempt))
+		}
+
+		select {
+		case <-ctx.Done():
+			return written, nil
+		case <-time.After(writer.Backoff << (attempt - 1)):
+		}
+	}
+}
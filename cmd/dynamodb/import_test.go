@@ -0,0 +1,179 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReadImportItemsJsonLines(t *testing.T) {
+	table, _ := ParseTableDescription(describeTableOutput)
+	input := strings.Join([]string{
+		`{"pk": {"S": "a"}, "createdAt": {"N": "1"}}`,
+		``,
+		`{"pk": {"S": "b"}}`,
+		`{"pk": {"S": "c"}, "createdAt": {"N": "1"}, "status": {"N": "2"}}`,
+		`{"pk": {"S": "a"}, "createdAt": {"N": "1"}, "note": {"S": "again"}}`,
+		`not json`,
+	}, "\n")
+
+	items, invalid, err := ReadImportItems(strings.NewReader(input), ExportJsonLinesDynamoDB, table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || *items[0]["pk"].S != "a" {
+		t.Errorf("Expected the first item only, got %v", items)
+	}
+
+	var lines []string
+	for _, importError := range invalid {
+		lines = append(lines, importError.Error())
+	}
+	expected := []string{
+		"line 3: key attribute createdAt is missing",
+		"line 4: key attribute status must be S, got N",
+		"line 5: same key as line 1",
+		"line 6: invalid character",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d invalid lines, got %v", len(expected), lines)
+	}
+	for index := range expected {
+		if !strings.HasPrefix(lines[index], expected[index]) {
+			t.Errorf("Got %q, expected %q", lines[index], expected[index])
+		}
+	}
+}
+
+func TestReadImportItemsPlainJsonLines(t *testing.T) {
+	table, _ := ParseTableDescription(describeTableOutput)
+	items, invalid, err := ReadImportItems(strings.NewReader(`{"pk": "a", "createdAt": 1, "tags": ["x"]}`), ExportJsonLinesPlain, table)
+	if err != nil || len(invalid) != 0 {
+		t.Fatalf("Unexpected errors: %v %v", err, invalid)
+	}
+	data, _ := json.Marshal(items[0])
+	expected := `{"createdAt":{"N":"1"},"pk":{"S":"a"},"tags":{"L":[{"S":"x"}]}}`
+	if string(data) != expected {
+		t.Errorf("Got %s, expected %s", data, expected)
+	}
+}
+
+func TestReadImportItemsCsv(t *testing.T) {
+	table, _ := ParseTableDescription(describeTableOutput)
+	input := "pk,createdAt,age,name,tags (SS),code (S),active\n" +
+		`007,1,36,"Ada, the first","[""a"",""b""]",42,true` + "\n" +
+		`b,x,,,,,` + "\n" +
+		`c,2,,,"{""a"": 1}",,` + "\n"
+
+	items, invalid, err := ReadImportItems(strings.NewReader(input), ExportCsv, table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected one item, got %v", items)
+	}
+	data, _ := json.Marshal(items[0])
+	expected := `{"active":{"BOOL":true},"age":{"N":"36"},"code":{"S":"42"},"createdAt":{"N":"1"},"name":{"S":"Ada, the first"},"pk":{"S":"007"},"tags":{"SS":["a","b"]}}`
+	if string(data) != expected {
+		t.Errorf("Got %s, expected %s", data, expected)
+	}
+
+	if len(invalid) != 2 || invalid[0].Line != 3 || invalid[1].Line != 4 {
+		t.Errorf("Expected lines 3 and 4 to be invalid, got %v", invalid)
+	}
+}
+
+// fakeBatchWriteItem leaves the last item of the first attempts unprocessed, and fails batches holding the item "bad"
+func fakeBatchWriteItem(unprocessedAttempts int) (func(ctx context.Context, requestItems string) (string, error), *int) {
+	calls := 0
+	return func(ctx context.Context, requestItems string) (string, error) {
+		calls++
+		var request map[string][]map[string]map[string]Item
+		if err := json.Unmarshal([]byte(requestItems), &request); err != nil {
+			return "", err
+		}
+		puts := request["orders"]
+		for _, put := range puts {
+			if *put["PutRequest"]["Item"]["pk"].S == "bad" {
+				return "", fmt.Errorf("ValidationException")
+			}
+		}
+		if calls <= unprocessedAttempts {
+			unprocessed, _ := json.Marshal(map[string]interface{}{"orders": puts[len(puts)-1:]})
+			return fmt.Sprintf(`{"UnprocessedItems": %s}`, unprocessed), nil
+		}
+		return `{"UnprocessedItems": {}}`, nil
+	}, &calls
+}
+
+func importItems(count int) []Item {
+	items := make([]Item, count)
+	for index := range items {
+		pk := fmt.Sprintf("item-%d", index)
+		items[index] = Item{"pk": {S: &pk}}
+	}
+	return items
+}
+
+func TestBatchWriterRetriesUnprocessedItems(t *testing.T) {
+	run, calls := fakeBatchWriteItem(2)
+	writer := BatchWriter{TableName: "orders", Run: run, MaxAttempts: 5}
+
+	var batches []string
+	err := writer.Write(context.Background(), importItems(30), func(written int, rejected []RejectedItem) error {
+		batches = append(batches, fmt.Sprintf("%d/%d", written, len(rejected)))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The first batch of 25 needs 3 calls, the last one the 4th
+	if strings.Join(batches, " ") != "25/0 5/0" || *calls != 4 {
+		t.Errorf("Unexpected batches %v after %d calls", batches, *calls)
+	}
+}
+
+func TestBatchWriterRejectsItems(t *testing.T) {
+	run, _ := fakeBatchWriteItem(10)
+	writer := BatchWriter{TableName: "orders", Run: run, MaxAttempts: 2}
+
+	items := importItems(26)
+	bad := "bad"
+	items[25] = Item{"pk": {S: &bad}}
+
+	var rejected []RejectedItem
+	written := 0
+	err := writer.Write(context.Background(), items, func(batchWritten int, batchRejected []RejectedItem) error {
+		written += batchWritten
+		rejected = append(rejected, batchRejected...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if written != 24 || len(rejected) != 2 {
+		t.Fatalf("Expected 24 written and 2 rejected items, got %d and %v", written, rejected)
+	}
+	if *rejected[0].Item["pk"].S != "item-24" || rejected[0].Err.Error() != "still unprocessed after 2 attempts" {
+		t.Errorf("Unexpected rejection %v", rejected[0])
+	}
+	if *rejected[1].Item["pk"].S != "bad" || rejected[1].Err.Error() != "ValidationException" {
+		t.Errorf("Unexpected rejection %v", rejected[1])
+	}
+}
+
+func TestBatchWriterCancelled(t *testing.T) {
+	run, calls := fakeBatchWriteItem(0)
+	writer := BatchWriter{TableName: "orders", Run: run, MaxAttempts: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := writer.Write(ctx, importItems(60), func(written int, rejected []RejectedItem) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled || *calls != 1 {
+		t.Errorf("Expected a cancelled import after one batch, got %v after %d calls", err, *calls)
+	}
+}
@@ -0,0 +1,189 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Index types of the index list
+const (
+	IndexTypePrimary = "Primary Index"
+	IndexTypeGlobal  = "Global Secondary Index"
+	IndexTypeLocal   = "Local Secondary Index"
+)
+
+// PrimaryIndexName is the name of the table keys in the index list
+const PrimaryIndexName = "Primary"
+
+// Projection types of a secondary index
+const (
+	ProjectionAll      = "ALL"
+	ProjectionKeysOnly = "KEYS_ONLY"
+	ProjectionInclude  = "INCLUDE"
+)
+
+// IndexStatusActive is the status of a global secondary index that can be queried
+const IndexStatusActive = "ACTIVE"
+
+// IndexKey is a key attribute of an index
+type IndexKey struct {
+	Name          string
+	KeyType       string // HASH or RANGE
+	AttributeType string // S, N or B
+}
+
+// IsPartitionKey reports whether the key is a partition key attribute, the other keys are sort key attributes
+func (key IndexKey) IsPartitionKey() bool {
+	return key.KeyType == KeyTypeHash
+}
+
+// Label describes the key as in the index list, e.g. "pk (PK:S)"
+func (key IndexKey) Label() string {
+	keyType := "SK"
+	if key.IsPartitionKey() {
+		keyType = "PK"
+	}
+	return fmt.Sprintf("%s (%s:%s)", key.Name, keyType, key.AttributeType)
+}
+
+// Index is the primary key or a secondary index of a table, as queried by the query form
+type Index struct {
+	Name        string
+	Type        string     // One of the index types
+	Keys        []IndexKey // Partition key attributes then sort key attributes, several for composite keys
+	Projection  Projection // ALL for the primary index
+	Status      string     // IndexStatus of global secondary indexes, empty for the other indexes
+	Backfilling bool       // A new global secondary index is being filled with the existing items
+}
+
+// Indexes returns the primary index followed by the global and local secondary indexes of the table
+func (table TableDescription) Indexes() []Index {
+	var indexes []Index
+	if keys := table.indexKeys(table.KeySchema); len(keys) > 0 {
+		indexes = append(indexes, Index{Name: PrimaryIndexName, Type: IndexTypePrimary, Keys: keys, Projection: Projection{ProjectionType: ProjectionAll}})
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		if keys := table.indexKeys(index.KeySchema); len(keys) > 0 {
+			indexes = append(indexes, Index{Name: index.IndexName, Type: IndexTypeGlobal, Keys: keys, Projection: index.Projection, Status: index.IndexStatus, Backfilling: index.Backfilling})
+		}
+	}
+	for _, index := range table.LocalSecondaryIndexes {
+		if keys := table.indexKeys(index.KeySchema); len(keys) > 0 {
+			indexes = append(indexes, Index{Name: index.IndexName, Type: IndexTypeLocal, Keys: keys, Projection: index.Projection})
+		}
+	}
+	return indexes
+}
+
+func (table TableDescription) indexKeys(schema []KeySchemaElement) []IndexKey {
+	var keys []IndexKey
+	for _, keyType := range []string{KeyTypeHash, KeyTypeRange} {
+		for _, element := range schema {
+			if element.KeyType == keyType {
+				keys = append(keys, IndexKey{Name: element.AttributeName, KeyType: keyType, AttributeType: table.AttributeType(element.AttributeName)})
+			}
+		}
+	}
+	return keys
+}
+
+// FindIndex returns the index of the table with the given name, PrimaryIndexName for the table keys
+func FindIndex(indexes []Index, name string) (Index, bool) {
+	for _, index := range indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return Index{}, false
+}
+
+// PartitionKeys returns the partition key attributes, every one of them is required by a query
+func (index Index) PartitionKeys() []IndexKey {
+	var keys []IndexKey
+	for _, key := range index.Keys {
+		if key.IsPartitionKey() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// SortKeys returns the sort key attributes, a query sets them from left to right
+func (index Index) SortKeys() []IndexKey {
+	var keys []IndexKey
+	for _, key := range index.Keys {
+		if !key.IsPartitionKey() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// IsSecondary reports whether the index is a global or local secondary index, queried with --index-name
+func (index Index) IsSecondary() bool {
+	return index.Type == IndexTypeGlobal || index.Type == IndexTypeLocal
+}
+
+// IsGlobal reports whether the index is a global secondary index, which only supports eventually consistent reads
+func (index Index) IsGlobal() bool {
+	return index.Type == IndexTypeGlobal
+}
+
+// KeyDetails describes the keys as in the index list, e.g. "status (PK:S), createdAt (SK:N)"
+func (index Index) KeyDetails() string {
+	labels := make([]string, len(index.Keys))
+	for i, key := range index.Keys {
+		labels[i] = key.Label()
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Summary describes the projection and the status of a secondary index, e.g. "KEYS_ONLY projection, CREATING"
+func (index Index) Summary() string {
+	if !index.IsSecondary() {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("%s projection", index.Projection.ProjectionType)}
+	if index.Status != "" {
+		parts = append(parts, index.Status)
+	}
+	if index.Backfilling {
+		parts = append(parts, "backfilling")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Notes warn about what a query of the index will not return.
+// tableKeys are the table key attributes, projected in every secondary index.
+func (index Index) Notes(tableKeys []string) []string {
+	if !index.IsSecondary() {
+		return nil
+	}
+
+	var notes []string
+	if index.Status != "" && index.Status != IndexStatusActive {
+		notes = append(notes, fmt.Sprintf("%s is %s, queries fail until it is %s", index.Name, index.Status, IndexStatusActive))
+	} else if index.Backfilling {
+		notes = append(notes, fmt.Sprintf("%s is backfilling, items written before its creation may be missing", index.Name))
+	}
+
+	projected := slices.Clone(tableKeys)
+	for _, key := range index.Keys {
+		if !slices.Contains(projected, key.Name) {
+			projected = append(projected, key.Name)
+		}
+	}
+	switch index.Projection.ProjectionType {
+	case ProjectionKeysOnly:
+		notes = append(notes, fmt.Sprintf("KEYS_ONLY projection: items only have %s", strings.Join(projected, ", ")))
+	case ProjectionInclude:
+		notes = append(notes, fmt.Sprintf("INCLUDE projection: items only have %s", strings.Join(append(projected, index.Projection.NonKeyAttributes...), ", ")))
+	}
+
+	var keyNames []string
+	for _, key := range index.Keys {
+		keyNames = append(keyNames, key.Name)
+	}
+	return append(notes, fmt.Sprintf("Sparse index: items without %s are not in %s", strings.Join(keyNames, ", "), index.Name))
+}
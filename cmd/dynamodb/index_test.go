@@ -0,0 +1,106 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const compositeIndexTableOutput = `{"Table": {
+	"TableName": "events",
+	"AttributeDefinitions": [
+		{"AttributeName": "pk", "AttributeType": "S"},
+		{"AttributeName": "tenant", "AttributeType": "S"},
+		{"AttributeName": "region", "AttributeType": "S"},
+		{"AttributeName": "day", "AttributeType": "S"},
+		{"AttributeName": "time", "AttributeType": "N"},
+		{"AttributeName": "createdAt", "AttributeType": "N"}
+	],
+	"KeySchema": [{"AttributeName": "pk", "KeyType": "HASH"}],
+	"GlobalSecondaryIndexes": [{
+		"IndexName": "byTenantRegion",
+		"KeySchema": [
+			{"AttributeName": "tenant", "KeyType": "HASH"},
+			{"AttributeName": "day", "KeyType": "RANGE"},
+			{"AttributeName": "region", "KeyType": "HASH"},
+			{"AttributeName": "time", "KeyType": "RANGE"}
+		],
+		"Projection": {"ProjectionType": "INCLUDE", "NonKeyAttributes": ["status"]},
+		"IndexStatus": "CREATING",
+		"Backfilling": true
+	}],
+	"LocalSecondaryIndexes": [{
+		"IndexName": "byCreatedAt",
+		"KeySchema": [{"AttributeName": "pk", "KeyType": "HASH"}, {"AttributeName": "createdAt", "KeyType": "RANGE"}],
+		"Projection": {"ProjectionType": "KEYS_ONLY"}
+	}]
+}}`
+
+func TestTableIndexes(t *testing.T) {
+	table, err := ParseTableDescription(compositeIndexTableOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	indexes := table.Indexes()
+	var names []string
+	for _, index := range indexes {
+		names = append(names, index.Name)
+	}
+	if !reflect.DeepEqual(names, []string{PrimaryIndexName, "byTenantRegion", "byCreatedAt"}) {
+		t.Fatalf("Unexpected indexes %v", names)
+	}
+
+	composite, ok := FindIndex(indexes, "byTenantRegion")
+	if !ok || composite.Type != IndexTypeGlobal || !composite.IsGlobal() || !composite.IsSecondary() {
+		t.Fatalf("Unexpected index %+v", composite)
+	}
+	// Partition key attributes first, each kind in key schema order
+	if details := composite.KeyDetails(); details != "tenant (PK:S), region (PK:S), day (SK:S), time (SK:N)" {
+		t.Errorf("Unexpected key details %q", details)
+	}
+	if len(composite.PartitionKeys()) != 2 || composite.SortKeys()[1].Name != "time" {
+		t.Errorf("Unexpected keys %+v", composite.Keys)
+	}
+	if summary := composite.Summary(); summary != "INCLUDE projection, CREATING, backfilling" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+
+	primary, _ := FindIndex(indexes, PrimaryIndexName)
+	if primary.IsSecondary() || primary.Summary() != "" || primary.Notes([]string{"pk"}) != nil {
+		t.Errorf("Unexpected primary index %+v", primary)
+	}
+
+	if _, ok := FindIndex(indexes, "missing"); ok {
+		t.Errorf("Expected no index named missing")
+	}
+}
+
+func TestIndexNotes(t *testing.T) {
+	table, err := ParseTableDescription(compositeIndexTableOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	indexes := table.Indexes()
+
+	composite, _ := FindIndex(indexes, "byTenantRegion")
+	expected := []string{
+		"byTenantRegion is CREATING, queries fail until it is ACTIVE",
+		"INCLUDE projection: items only have pk, tenant, region, day, time, status",
+		"Sparse index: items without tenant, region, day, time are not in byTenantRegion",
+	}
+	if notes := composite.Notes([]string{"pk"}); !reflect.DeepEqual(notes, expected) {
+		t.Errorf("Expected notes %q, got %q", expected, notes)
+	}
+
+	local, _ := FindIndex(indexes, "byCreatedAt")
+	notes := local.Notes([]string{"pk"})
+	if len(notes) != 2 || notes[0] != "KEYS_ONLY projection: items only have pk, createdAt" || !strings.HasPrefix(notes[1], "Sparse index") {
+		t.Errorf("Unexpected notes %q", notes)
+	}
+
+	composite.Status = IndexStatusActive
+	if notes := composite.Notes([]string{"pk"}); !strings.HasPrefix(notes[0], "byTenantRegion is backfilling") {
+		t.Errorf("Expected a backfilling note, got %q", notes)
+	}
+}
@@ -0,0 +1,419 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Item is a DynamoDB item, keyed by attribute name
+type Item map[string]AttributeValue
+
+// Type returns the DynamoDB type of the value (S, N, B, BOOL, NULL, SS, NS, BS, M or L)
+func (value AttributeValue) Type() string {
+	var types []string
+	if value.S != nil {
+		types = append(types, "S")
+	}
+	if value.N != nil {
+		types = append(types, "N")
+	}
+	if value.B != nil {
+		types = append(types, "B")
+	}
+	if value.BOOL != nil {
+		types = append(types, "BOOL")
+	}
+	if value.NULL != nil {
+		types = append(types, "NULL")
+	}
+	if value.SS != nil {
+		types = append(types, "SS")
+	}
+	if value.NS != nil {
+		types = append(types, "NS")
+	}
+	if value.BS != nil {
+		types = append(types, "BS")
+	}
+	if value.M != nil {
+		types = append(types, "M")
+	}
+	if value.L != nil {
+		types = append(types, "L")
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
+
+// Validate checks the value holds exactly one type, with numbers and base64 binaries where expected
+func (value AttributeValue) Validate() error {
+	switch value.Type() {
+	case "":
+		return fmt.Errorf("a value must have exactly one type")
+	case "N":
+		_, err := NewAttributeValue("N", *value.N)
+		return err
+	case "B":
+		_, err := NewAttributeValue("B", *value.B)
+		return err
+	case "NULL":
+		if !*value.NULL {
+			return fmt.Errorf("NULL must be true")
+		}
+	case "NS":
+		for _, number := range value.NS {
+			if _, err := NewAttributeValue("N", number); err != nil {
+				return err
+			}
+		}
+	case "BS":
+		for _, binary := range value.BS {
+			if _, err := NewAttributeValue("B", binary); err != nil {
+				return err
+			}
+		}
+	case "M":
+		return Item(value.M).Validate()
+	case "L":
+		for index, element := range value.L {
+			if err := element.Validate(); err != nil {
+				return fmt.Errorf("[%d]: %w", index, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Equal reports whether both values have the same type and content
+func (value AttributeValue) Equal(other AttributeValue) bool {
+	valueJson, _ := json.Marshal(value)
+	otherJson, _ := json.Marshal(other)
+	return bytes.Equal(valueJson, otherJson)
+}
+
+// Plain returns the value as plain JSON value, numbers keep their precision as json.Number
+func (value AttributeValue) Plain() interface{} {
+	switch value.Type() {
+	case "S":
+		return *value.S
+	case "N":
+		return json.Number(*value.N)
+	case "B":
+		return *value.B
+	case "BOOL":
+		return *value.BOOL
+	case "SS":
+		return value.SS
+	case "NS":
+		numbers := make([]json.Number, len(value.NS))
+		for index, number := range value.NS {
+			numbers[index] = json.Number(number)
+		}
+		return numbers
+	case "BS":
+		return value.BS
+	case "M":
+		return Item(value.M).Plain()
+	case "L":
+		elements := make([]interface{}, len(value.L))
+		for index, element := range value.L {
+			elements[index] = element.Plain()
+		}
+		return elements
+	}
+	return nil
+}
+
+// ParseItem decodes and validates an item in DynamoDB JSON
+func ParseItem(data []byte) (Item, error) {
+	var item Item
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&item); err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("an item must be a JSON object")
+	}
+	return item, item.Validate()
+}
+
+// ParsePlainItem decodes an item in plain JSON. Strings, numbers, booleans, null, arrays and objects become
+// S, N, BOOL, NULL, L and M, unless the same attribute of the original item was binary or a set.
+func ParsePlainItem(data []byte, original Item) (Item, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var plain map[string]interface{}
+	if err := decoder.Decode(&plain); err != nil {
+		return nil, err
+	}
+	if plain == nil {
+		return nil, fmt.Errorf("an item must be a JSON object")
+	}
+
+	item := Item{}
+	for name, value := range plain {
+		var hint *AttributeValue
+		if originalValue, exists := original[name]; exists {
+			hint = &originalValue
+		}
+		converted, err := fromPlain(value, hint)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		item[name] = converted
+	}
+	return item, item.Validate()
+}
+
+func fromPlain(value interface{}, hint *AttributeValue) (AttributeValue, error) {
+	hintType := ""
+	if hint != nil {
+		hintType = hint.Type()
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return NewAttributeValue("NULL", "")
+	case bool:
+		return AttributeValue{BOOL: &v}, nil
+	case json.Number:
+		return NewAttributeValue("N", v.String())
+	case string:
+		if hintType == "B" {
+			return NewAttributeValue("B", v)
+		}
+		return AttributeValue{S: &v}, nil
+	case []interface{}:
+		if hintType == "SS" || hintType == "NS" || hintType == "BS" {
+			return setFromPlain(v, hintType)
+		}
+		list := make([]AttributeValue, len(v))
+		for index, element := range v {
+			var elementHint *AttributeValue
+			if hintType == "L" && index < len(hint.L) {
+				elementHint = &hint.L[index]
+			}
+			converted, err := fromPlain(element, elementHint)
+			if err != nil {
+				return AttributeValue{}, fmt.Errorf("[%d]: %w", index, err)
+			}
+			list[index] = converted
+		}
+		return AttributeValue{L: list}, nil
+	case map[string]interface{}:
+		m := map[string]AttributeValue{}
+		for name, element := range v {
+			var elementHint *AttributeValue
+			if hintType == "M" {
+				if originalElement, exists := hint.M[name]; exists {
+					elementHint = &originalElement
+				}
+			}
+			converted, err := fromPlain(element, elementHint)
+			if err != nil {
+				return AttributeValue{}, fmt.Errorf("%s: %w", name, err)
+			}
+			m[name] = converted
+		}
+		return AttributeValue{M: m}, nil
+	}
+	return AttributeValue{}, fmt.Errorf("unsupported value %v", value)
+}
+
+func setFromPlain(elements []interface{}, setType string) (AttributeValue, error) {
+	values := make([]string, len(elements))
+	for index, element := range elements {
+		switch v := element.(type) {
+		case string:
+			values[index] = v
+		case json.Number:
+			values[index] = v.String()
+		default:
+			return AttributeValue{}, fmt.Errorf("%s elements must be strings or numbers, got %v", setType, element)
+		}
+	}
+	switch setType {
+	case "SS":
+		return AttributeValue{SS: values}, nil
+	case "NS":
+		return AttributeValue{NS: values}, nil
+	}
+	return AttributeValue{BS: values}, nil
+}
+
+// Validate checks every attribute of the item
+func (item Item) Validate() error {
+	for _, name := range item.names() {
+		if err := item[name].Validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Plain returns the item as plain JSON object
+func (item Item) Plain() map[string]interface{} {
+	plain := make(map[string]interface{}, len(item))
+	for name, value := range item {
+		plain[name] = value.Plain()
+	}
+	return plain
+}
+
+// Key returns the key attributes of the item, failing when one of them is missing
+func (item Item) Key(keyAttributes []string) (Item, error) {
+	key := Item{}
+	for _, name := range keyAttributes {
+		value, exists := item[name]
+		if !exists {
+			return nil, fmt.Errorf("key attribute %s is missing", name)
+		}
+		key[name] = value
+	}
+	return key, nil
+}
+
+func (item Item) names() []string {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Update is an update-item request changing an item into another one
+type Update struct {
+	Key                 Item
+	UpdateExpression    string
+	ConditionExpression string // Set when guarded, fails the update when the item changed since it was read
+	Names               map[string]string
+	Values              map[string]AttributeValue
+}
+
+// NewUpdate returns the update setting the changed attributes and removing the missing ones.
+// Key attributes can not change. With guard, every changed attribute must still hold its original value.
+func NewUpdate(original Item, edited Item, keyAttributes []string, guard bool) (Update, error) {
+	if err := CheckKeyUnchanged(original, edited, keyAttributes); err != nil {
+		return Update{}, err
+	}
+	key, err := original.Key(keyAttributes)
+	if err != nil {
+		return Update{}, err
+	}
+
+	update := Update{Key: key, Names: map[string]string{}, Values: map[string]AttributeValue{}}
+	var sets, removes, conditions []string
+	isKey := make(map[string]bool)
+	for _, name := range keyAttributes {
+		isKey[name] = true
+	}
+
+	names := original.names()
+	for _, name := range edited.names() {
+		if _, exists := original[name]; !exists {
+			names = append(names, name)
+		}
+	}
+
+	for index, name := range names {
+		if isKey[name] {
+			continue
+		}
+		originalValue, inOriginal := original[name]
+		editedValue, inEdited := edited[name]
+		if inOriginal && inEdited && originalValue.Equal(editedValue) {
+			continue
+		}
+
+		placeholder := fmt.Sprintf("#u%d", index)
+		update.Names[placeholder] = name
+
+		if inEdited {
+			valuePlaceholder := fmt.Sprintf(":u%d", index)
+			update.Values[valuePlaceholder] = editedValue
+			sets = append(sets, fmt.Sprintf("%s = %s", placeholder, valuePlaceholder))
+		} else {
+			removes = append(removes, placeholder)
+		}
+
+		if guard {
+			if inOriginal {
+				originalPlaceholder := fmt.Sprintf(":o%d", index)
+				update.Values[originalPlaceholder] = originalValue
+				conditions = append(conditions, fmt.Sprintf("%s = %s", placeholder, originalPlaceholder))
+			} else {
+				conditions = append(conditions, fmt.Sprintf("attribute_not_exists(%s)", placeholder))
+			}
+		}
+	}
+
+	if len(sets) == 0 && len(removes) == 0 {
+		return Update{}, fmt.Errorf("the item has no changes")
+	}
+
+	var clauses []string
+	if len(sets) > 0 {
+		clauses = append(clauses, "SET "+strings.Join(sets, ", "))
+	}
+	if len(removes) > 0 {
+		clauses = append(clauses, "REMOVE "+strings.Join(removes, ", "))
+	}
+	update.UpdateExpression = strings.Join(clauses, " ")
+	update.ConditionExpression = strings.Join(conditions, " AND ")
+
+	return update, nil
+}
+
+// Arguments returns the update-item arguments of the update, next to --table-name
+func (update Update) Arguments() ([]string, error) {
+	keyJson, err := json.Marshal(update.Key)
+	if err != nil {
+		return nil, err
+	}
+	namesJson, err := json.Marshal(update.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := []string{"--key", string(keyJson), "--update-expression", update.UpdateExpression}
+	if update.ConditionExpression != "" {
+		arguments = append(arguments, "--condition-expression", update.ConditionExpression)
+	}
+	arguments = append(arguments, "--expression-attribute-names", string(namesJson))
+
+	// A REMOVE only update has no values, and empty values are rejected by DynamoDB
+	if len(update.Values) > 0 {
+		valuesJson, err := json.Marshal(update.Values)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, "--expression-attribute-values", string(valuesJson))
+	}
+	return arguments, nil
+}
+
+// CheckKeyUnchanged fails when a key attribute differs between both items, writing it would create another item.
+// Without key attributes nothing can be checked, so it fails as well.
+func CheckKeyUnchanged(original Item, edited Item, keyAttributes []string) error {
+	if len(keyAttributes) == 0 {
+		return fmt.Errorf("the key attributes of the table are unknown")
+	}
+	for _, name := range keyAttributes {
+		editedValue, exists := edited[name]
+		if !exists {
+			return fmt.Errorf("key attribute %s is missing", name)
+		}
+		if !original[name].Equal(editedValue) {
+			return fmt.Errorf("key attribute %s can not be changed", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseItemValidation(t *testing.T) {
+	invalid := map[string]string{
+		"not an object":   `["a"]`,
+		"unknown type":    `{"id": {"X": "a"}}`,
+		"two types":       `{"id": {"S": "a", "N": "1"}}`,
+		"no type":         `{"id": {}}`,
+		"invalid number":  `{"age": {"N": "old"}}`,
+		"invalid binary":  `{"data": {"B": "not base64!"}}`,
+		"invalid set":     `{"scores": {"NS": ["1", "x"]}}`,
+		"nested invalid":  `{"profile": {"M": {"age": {"N": "x"}}}}`,
+		"false null":      `{"gone": {"NULL": false}}`,
+		"invalid in list": `{"tags": {"L": [{"S": "a"}, {"N": "b"}]}}`,
+	}
+
+	for name, input := range invalid {
+		if _, err := ParseItem([]byte(input)); err == nil {
+			t.Errorf("%s: expected an error for %s", name, input)
+		}
+	}
+
+	item, err := ParseItem([]byte(`{"id": {"S": "a"}, "tags": {"L": []}, "scores": {"NS": ["1", "2.5"]}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item["scores"].Type() != "NS" || item["tags"].Type() != "L" {
+		t.Errorf("Unexpected types: %s, %s", item["scores"].Type(), item["tags"].Type())
+	}
+}
+
+func TestParsePlainItemKeepsOriginalTypes(t *testing.T) {
+	original, _ := ParseItem([]byte(`{"data": {"B": "aGVsbG8="}, "tags": {"SS": ["a"]}, "scores": {"NS": ["1"]}}`))
+
+	item, err := ParsePlainItem([]byte(`{
+		"data": "d29ybGQ=",
+		"tags": ["a", "b"],
+		"scores": [1, 12345678901234567890],
+		"list": ["a", 1],
+		"profile": {"active": true, "nickname": null},
+		"price": 1.50
+	}`), original)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	marshalled, _ := json.Marshal(item)
+	expected := `{"data":{"B":"d29ybGQ="},` +
+		`"list":{"L":[{"S":"a"},{"N":"1"}]},` +
+		`"price":{"N":"1.50"},` +
+		`"profile":{"M":{"active":{"BOOL":true},"nickname":{"NULL":true}}},` +
+		`"scores":{"NS":["1","12345678901234567890"]},` +
+		`"tags":{"SS":["a","b"]}}`
+	if string(marshalled) != expected {
+		t.Errorf("Got %s, expected %s", marshalled, expected)
+	}
+
+	if _, err := ParsePlainItem([]byte(`{"data": "not base64!"}`), original); err == nil {
+		t.Error("Expected an error for an invalid binary value")
+	}
+}
+
+func TestItemPlainRoundTrip(t *testing.T) {
+	input := `{"age":{"N":"42"},"profile":{"M":{"tags":{"L":[{"S":"a"},{"BOOL":false}]}}},"scores":{"NS":["1","2"]}}`
+	item, err := ParseItem([]byte(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plain, _ := json.Marshal(item.Plain())
+	expectedPlain := `{"age":42,"profile":{"tags":["a",false]},"scores":[1,2]}`
+	if string(plain) != expectedPlain {
+		t.Errorf("Got %s, expected %s", plain, expectedPlain)
+	}
+
+	parsed, err := ParsePlainItem(plain, item)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	marshalled, _ := json.Marshal(parsed)
+	if string(marshalled) != input {
+		t.Errorf("Got %s, expected %s", marshalled, input)
+	}
+}
+
+func TestNewUpdate(t *testing.T) {
+	original, _ := ParseItem([]byte(`{"id": {"S": "1"}, "name": {"S": "old"}, "age": {"N": "3"}, "note": {"S": "x"}}`))
+	edited, _ := ParseItem([]byte(`{"id": {"S": "1"}, "name": {"S": "new"}, "age": {"N": "3"}, "city": {"S": "Rome"}}`))
+
+	update, err := NewUpdate(original, edited, []string{"id"}, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Placeholders are numbered after the sorted original attributes, followed by the added ones
+	if update.UpdateExpression != "SET #u2 = :u2, #u4 = :u4 REMOVE #u3" {
+		t.Errorf("Unexpected update expression: %s", update.UpdateExpression)
+	}
+	if update.ConditionExpression != "#u2 = :o2 AND #u3 = :o3 AND attribute_not_exists(#u4)" {
+		t.Errorf("Unexpected condition expression: %s", update.ConditionExpression)
+	}
+	if update.Names["#u2"] != "name" || update.Names["#u3"] != "note" || update.Names["#u4"] != "city" {
+		t.Errorf("Unexpected names: %v", update.Names)
+	}
+
+	arguments, err := update.Arguments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(arguments, " ")
+	if !strings.HasPrefix(joined, `--key {"id":{"S":"1"}} --update-expression`) {
+		t.Errorf("Unexpected arguments: %s", joined)
+	}
+	if !strings.Contains(joined, `":o3":{"S":"x"}`) || !strings.Contains(joined, `":u4":{"S":"Rome"}`) {
+		t.Errorf("Missing values in arguments: %s", joined)
+	}
+}
+
+func TestNewUpdateWithoutGuard(t *testing.T) {
+	original, _ := ParseItem([]byte(`{"id": {"S": "1"}, "note": {"S": "x"}}`))
+	edited, _ := ParseItem([]byte(`{"id": {"S": "1"}}`))
+
+	update, err := NewUpdate(original, edited, []string{"id"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	arguments, _ := update.Arguments()
+	for _, argument := range arguments {
+		if argument == "--condition-expression" || argument == "--expression-attribute-values" {
+			t.Errorf("Unexpected argument %s in %v", argument, arguments)
+		}
+	}
+}
+
+func TestNewUpdateErrors(t *testing.T) {
+	original, _ := ParseItem([]byte(`{"id": {"S": "1"}, "name": {"S": "a"}}`))
+	changedKey, _ := ParseItem([]byte(`{"id": {"S": "2"}, "name": {"S": "a"}}`))
+
+	if _, err := NewUpdate(original, original, []string{"id"}, false); err == nil {
+		t.Error("Expected an error for an update without changes")
+	}
+	if _, err := NewUpdate(original, changedKey, []string{"id"}, false); err == nil {
+		t.Error("Expected an error for a changed key")
+	}
+	if _, err := NewUpdate(original, changedKey, nil, false); err == nil {
+		t.Error("Expected an error for unknown key attributes")
+	}
+	if err := CheckKeyUnchanged(original, changedKey, nil); err == nil {
+		t.Error("Expected an error checking unknown key attributes")
+	}
+}
@@ -0,0 +1,243 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timestamp is a date of the AWS CLI output: an ISO 8601 string, or seconds since the epoch with cli_timestamp_format none
+type Timestamp string
+
+func (timestamp *Timestamp) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*timestamp = Timestamp(text)
+		return nil
+	}
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("unexpected date %s", data)
+	}
+	*timestamp = Timestamp(time.UnixMilli(int64(seconds * 1000)).UTC().Format(time.RFC3339))
+	return nil
+}
+
+// TimeToLive is the TimeToLiveDescription of the describe-time-to-live output
+type TimeToLive struct {
+	TimeToLiveStatus string `json:"TimeToLiveStatus"`
+	AttributeName    string `json:"AttributeName"`
+}
+
+// ParseTimeToLive decodes the output of describe-time-to-live
+func ParseTimeToLive(output string) (TimeToLive, error) {
+	var result struct {
+		TimeToLiveDescription *TimeToLive `json:"TimeToLiveDescription"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return TimeToLive{}, err
+	}
+	if result.TimeToLiveDescription == nil {
+		return TimeToLive{}, fmt.Errorf("describe-time-to-live output has no TimeToLiveDescription attribute")
+	}
+	return *result.TimeToLiveDescription, nil
+}
+
+// ContinuousBackups is the ContinuousBackupsDescription of the describe-continuous-backups output
+type ContinuousBackups struct {
+	ContinuousBackupsStatus        string `json:"ContinuousBackupsStatus"`
+	PointInTimeRecoveryDescription struct {
+		PointInTimeRecoveryStatus  string    `json:"PointInTimeRecoveryStatus"`
+		RecoveryPeriodInDays       int       `json:"RecoveryPeriodInDays"`
+		EarliestRestorableDateTime Timestamp `json:"EarliestRestorableDateTime"`
+		LatestRestorableDateTime   Timestamp `json:"LatestRestorableDateTime"`
+	} `json:"PointInTimeRecoveryDescription"`
+}
+
+// ParseContinuousBackups decodes the output of describe-continuous-backups
+func ParseContinuousBackups(output string) (ContinuousBackups, error) {
+	var result struct {
+		ContinuousBackupsDescription *ContinuousBackups `json:"ContinuousBackupsDescription"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return ContinuousBackups{}, err
+	}
+	if result.ContinuousBackupsDescription == nil {
+		return ContinuousBackups{}, fmt.Errorf("describe-continuous-backups output has no ContinuousBackupsDescription attribute")
+	}
+	return *result.ContinuousBackupsDescription, nil
+}
+
+// TableOverview gathers the table metadata, the time to live and backups are left nil when they can not be described
+type TableOverview struct {
+	Table                  TableDescription
+	TimeToLive             *TimeToLive
+	TimeToLiveError        error
+	ContinuousBackups      *ContinuousBackups
+	ContinuousBackupsError error
+}
+
+// Rows returns the overview as section, property and value rows, in display order
+func (overview TableOverview) Rows() [][]string {
+	table := overview.Table
+	var rows [][]string
+	add := func(section string, property string, value interface{}) {
+		rows = append(rows, []string{section, property, fmt.Sprint(value)})
+	}
+
+	add("Table", "Name", table.TableName)
+	add("Table", "ARN", table.TableArn)
+	add("Table", "Status", table.TableStatus)
+	add("Table", "Keys", keyDetails(table.KeySchema, table))
+	add("Table", "Created", table.CreationDateTime)
+	add("Table", "Items (updated about every 6 hours)", table.ItemCount)
+	add("Table", "Size", FormatBytes(table.TableSizeBytes))
+	add("Table", "Table class", tableClass(table))
+	add("Table", "Deletion protection", enabledText(table.DeletionProtectionEnabled))
+	add("Table", "Encryption", encryption(table))
+
+	billingMode := billingMode(table)
+	add("Capacity", "Billing mode", billingMode)
+	add("Capacity", "Table", throughput(billingMode, table.ProvisionedThroughput))
+
+	if table.StreamSpecification != nil && table.StreamSpecification.StreamEnabled {
+		add("Stream", "Status", fmt.Sprintf("Enabled (%s)", table.StreamSpecification.StreamViewType))
+		add("Stream", "Latest stream ARN", table.LatestStreamArn)
+	} else {
+		add("Stream", "Status", "Disabled")
+	}
+
+	if overview.TimeToLive != nil {
+		add("Time to live", "Status", overview.TimeToLive.TimeToLiveStatus)
+		if overview.TimeToLive.AttributeName != "" {
+			add("Time to live", "Attribute", overview.TimeToLive.AttributeName)
+		}
+	} else {
+		add("Time to live", "Status", unavailable(overview.TimeToLiveError))
+	}
+
+	if overview.ContinuousBackups != nil {
+		recovery := overview.ContinuousBackups.PointInTimeRecoveryDescription
+		add("Backups", "Continuous backups", overview.ContinuousBackups.ContinuousBackupsStatus)
+		add("Backups", "Point-in-time recovery", recovery.PointInTimeRecoveryStatus)
+		if recovery.RecoveryPeriodInDays > 0 {
+			add("Backups", "Recovery period (days)", recovery.RecoveryPeriodInDays)
+		}
+		if recovery.EarliestRestorableDateTime != "" {
+			add("Backups", "Earliest restorable", recovery.EarliestRestorableDateTime)
+			add("Backups", "Latest restorable", recovery.LatestRestorableDateTime)
+		}
+	} else {
+		add("Backups", "Continuous backups", unavailable(overview.ContinuousBackupsError))
+	}
+
+	addIndexes := func(indexType string, list []SecondaryIndex) {
+		for _, index := range list {
+			section := fmt.Sprintf("Index %s", index.IndexName)
+			add(section, "Type", indexType)
+			add(section, "Keys", keyDetails(index.KeySchema, table))
+			add(section, "Projection", projection(index.Projection))
+			if index.IndexStatus != "" {
+				add(section, "Status", index.IndexStatus)
+			}
+			if indexType == "Global secondary index" {
+				add(section, "Capacity", throughput(billingMode, index.ProvisionedThroughput))
+			}
+			add(section, "Items", index.ItemCount)
+			add(section, "Size", FormatBytes(index.IndexSizeBytes))
+		}
+	}
+	addIndexes("Global secondary index", table.GlobalSecondaryIndexes)
+	addIndexes("Local secondary index", table.LocalSecondaryIndexes)
+
+	return rows
+}
+
+// FormatBytes returns a size in bytes with a decimal unit, e.g. "1.5 MB"
+func FormatBytes(size int64) string {
+	if size < 1000 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	for _, unit := range []string{"KB", "MB", "GB", "TB"} {
+		value /= 1000
+		if value < 1000 || unit == "TB" {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+	}
+	return ""
+}
+
+func billingMode(table TableDescription) string {
+	// Tables created with provisioned capacity may have no billing mode summary
+	if table.BillingModeSummary == nil || table.BillingModeSummary.BillingMode == "" {
+		return "PROVISIONED"
+	}
+	return table.BillingModeSummary.BillingMode
+}
+
+func throughput(billingMode string, provisioned *ProvisionedThroughput) string {
+	if billingMode == "PAY_PER_REQUEST" || provisioned == nil {
+		return "On-demand"
+	}
+	return fmt.Sprintf("%d read / %d write capacity units", provisioned.ReadCapacityUnits, provisioned.WriteCapacityUnits)
+}
+
+func tableClass(table TableDescription) string {
+	if table.TableClassSummary == nil || table.TableClassSummary.TableClass == "" {
+		return "STANDARD"
+	}
+	return table.TableClassSummary.TableClass
+}
+
+func encryption(table TableDescription) string {
+	// Without description the table is encrypted with a key owned by DynamoDB
+	if table.SSEDescription == nil {
+		return "AWS owned key"
+	}
+	text := fmt.Sprintf("%s (%s)", table.SSEDescription.SSEType, table.SSEDescription.Status)
+	if table.SSEDescription.KMSMasterKeyArn != "" {
+		text = fmt.Sprintf("%s, %s", text, table.SSEDescription.KMSMasterKeyArn)
+	}
+	return text
+}
+
+func projection(projection Projection) string {
+	if len(projection.NonKeyAttributes) == 0 {
+		return projection.ProjectionType
+	}
+	return fmt.Sprintf("%s (%s)", projection.ProjectionType, strings.Join(projection.NonKeyAttributes, ", "))
+}
+
+// keyDetails describes a key schema like the index list, e.g. "pk (PK:S), createdAt (SK:N)"
+func keyDetails(schema []KeySchemaElement, table TableDescription) string {
+	var keys []string
+	for _, keyType := range []string{KeyTypeHash, KeyTypeRange} {
+		for _, element := range schema {
+			if element.KeyType != keyType {
+				continue
+			}
+			displayKeyType := "PK"
+			if keyType == KeyTypeRange {
+				displayKeyType = "SK"
+			}
+			keys = append(keys, fmt.Sprintf("%s (%s:%s)", element.AttributeName, displayKeyType, table.AttributeType(element.AttributeName)))
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+func enabledText(enabled bool) string {
+	if enabled {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+
+func unavailable(err error) string {
+	if err == nil {
+		return "Unavailable"
+	}
+	return fmt.Sprintf("Unavailable: %s", strings.Join(strings.Fields(err.Error()), " "))
+}
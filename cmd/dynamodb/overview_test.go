@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const describeProvisionedTableOutput = `{"Table": {
+	"TableName": "orders",
+	"TableArn": "arn:aws:dynamodb:eu-west-1:123456789012:table/orders",
+	"TableStatus": "ACTIVE",
+	"CreationDateTime": 1700000000.5,
+	"ItemCount": 42,
+	"TableSizeBytes": 1536000,
+	"AttributeDefinitions": [
+		{"AttributeName": "pk", "AttributeType": "S"},
+		{"AttributeName": "status", "AttributeType": "S"}
+	],
+	"KeySchema": [{"AttributeName": "pk", "KeyType": "HASH"}],
+	"ProvisionedThroughput": {"ReadCapacityUnits": 5, "WriteCapacityUnits": 10},
+	"StreamSpecification": {"StreamEnabled": true, "StreamViewType": "NEW_AND_OLD_IMAGES"},
+	"LatestStreamArn": "arn:stream",
+	"SSEDescription": {"Status": "ENABLED", "SSEType": "KMS", "KMSMasterKeyArn": "arn:key"},
+	"DeletionProtectionEnabled": true,
+	"GlobalSecondaryIndexes": [{
+		"IndexName": "byStatus",
+		"KeySchema": [{"AttributeName": "status", "KeyType": "HASH"}],
+		"Projection": {"ProjectionType": "INCLUDE", "NonKeyAttributes": ["total", "createdAt"]},
+		"IndexStatus": "ACTIVE",
+		"ProvisionedThroughput": {"ReadCapacityUnits": 1, "WriteCapacityUnits": 2},
+		"ItemCount": 40,
+		"IndexSizeBytes": 900
+	}]
+}}`
+
+func overviewValues(overview TableOverview) map[string]string {
+	values := make(map[string]string)
+	for _, row := range overview.Rows() {
+		values[row[0]+"/"+row[1]] = row[2]
+	}
+	return values
+}
+
+func TestTableOverviewRows(t *testing.T) {
+	table, err := ParseTableDescription(describeProvisionedTableOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	timeToLive, err := ParseTimeToLive(`{"TimeToLiveDescription": {"TimeToLiveStatus": "ENABLED", "AttributeName": "expiresAt"}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backups, err := ParseContinuousBackups(`{"ContinuousBackupsDescription": {"ContinuousBackupsStatus": "ENABLED",
+		"PointInTimeRecoveryDescription": {"PointInTimeRecoveryStatus": "ENABLED", "RecoveryPeriodInDays": 35,
+		"EarliestRestorableDateTime": "2024-01-01T00:00:00+00:00", "LatestRestorableDateTime": "2024-02-01T00:00:00+00:00"}}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	values := overviewValues(TableOverview{Table: table, TimeToLive: &timeToLive, ContinuousBackups: &backups})
+	expected := map[string]string{
+		"Table/Keys":    "pk (PK:S)",
+		"Table/Created": "2023-11-14T22:13:20Z",
+		"Table/Items (updated about every 6 hours)": "42",
+		"Table/Size":                     "1.5 MB",
+		"Table/Table class":              "STANDARD",
+		"Table/Deletion protection":      "Enabled",
+		"Table/Encryption":               "KMS (ENABLED), arn:key",
+		"Capacity/Billing mode":          "PROVISIONED",
+		"Capacity/Table":                 "5 read / 10 write capacity units",
+		"Stream/Status":                  "Enabled (NEW_AND_OLD_IMAGES)",
+		"Time to live/Attribute":         "expiresAt",
+		"Backups/Point-in-time recovery": "ENABLED",
+		"Backups/Recovery period (days)": "35",
+		"Backups/Earliest restorable":    "2024-01-01T00:00:00+00:00",
+		"Index byStatus/Projection":      "INCLUDE (total, createdAt)",
+		"Index byStatus/Capacity":        "1 read / 2 write capacity units",
+		"Index byStatus/Size":            "900 B",
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("%s: got %q, expected %q", key, values[key], value)
+		}
+	}
+}
+
+func TestTableOverviewWithoutPermissions(t *testing.T) {
+	table, _ := ParseTableDescription(describeTableOutput)
+	values := overviewValues(TableOverview{
+		Table:                  table,
+		TimeToLiveError:        fmt.Errorf("An error occurred (AccessDeniedException)\n when calling"),
+		ContinuousBackupsError: fmt.Errorf("denied"),
+	})
+
+	if values["Time to live/Status"] != "Unavailable: An error occurred (AccessDeniedException) when calling" {
+		t.Errorf("Unexpected time to live status %q", values["Time to live/Status"])
+	}
+	if values["Backups/Continuous backups"] != "Unavailable: denied" {
+		t.Errorf("Unexpected backups status %q", values["Backups/Continuous backups"])
+	}
+	if values["Stream/Status"] != "Disabled" || values["Table/Encryption"] != "AWS owned key" {
+		t.Errorf("Unexpected defaults %v", values)
+	}
+	if !strings.HasPrefix(values["Index byStatus/Type"], "Global") {
+		t.Errorf("Expected the byStatus index, got %v", values)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for size, expected := range map[int64]string{0: "0 B", 999: "999 B", 1000: "1.0 KB", 2500000000: "2.5 GB"} {
+		if formatted := FormatBytes(size); formatted != expected {
+			t.Errorf("%d: got %q, expected %q", size, formatted, expected)
+		}
+	}
+}
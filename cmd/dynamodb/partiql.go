@@ -0,0 +1,88 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Statement is a PartiQL statement run by execute-statement
+type Statement struct {
+	Statement  string
+	Parameters string // Optional JSON list of DynamoDB values replacing the ? of the statement
+}
+
+// Arguments returns the execute-statement arguments of the statement
+func (statement Statement) Arguments() ([]string, error) {
+	text := strings.TrimSpace(statement.Statement)
+	if text == "" {
+		return nil, fmt.Errorf("the statement is empty")
+	}
+	arguments := []string{"--statement", text}
+
+	if strings.TrimSpace(statement.Parameters) == "" {
+		return arguments, nil
+	}
+
+	var parameters []AttributeValue
+	decoder := json.NewDecoder(bytes.NewReader([]byte(statement.Parameters)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&parameters); err != nil {
+		return nil, fmt.Errorf("parameters must be a JSON list of DynamoDB values: %w", err)
+	}
+	for index, parameter := range parameters {
+		if err := parameter.Validate(); err != nil {
+			return nil, fmt.Errorf("parameter %d: %w", index+1, err)
+		}
+	}
+
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, err
+	}
+	return append(arguments, "--parameters", string(parametersJson)), nil
+}
+
+// Keyword returns the leading keyword of the statement in upper case, e.g. "SELECT" or "DELETE"
+func (statement Statement) Keyword() string {
+	text := strings.TrimSpace(statement.Statement)
+	end := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(text)
+	}
+	return strings.ToUpper(text[:end])
+}
+
+// IsRead reports whether the statement only reads items, INSERT, UPDATE and DELETE statements write
+func (statement Statement) IsRead() bool {
+	return statement.Keyword() == "SELECT"
+}
+
+// StatementHistoryLimit is the number of statements kept per profile and table
+const StatementHistoryLimit = 20
+
+// StatementHistory holds the PartiQL statements run per profile and table, most recent first
+type StatementHistory map[string][]string
+
+func tableStateKey(profile string, table string) string {
+	return profile + "/" + table
+}
+
+// Add puts the statement first in the history of the table, removing an older run of the same statement
+func (history StatementHistory) Add(profile string, table string, statement string) {
+	key := tableStateKey(profile, table)
+	statements := []string{statement}
+	for _, previous := range history[key] {
+		if previous != statement && len(statements) < StatementHistoryLimit {
+			statements = append(statements, previous)
+		}
+	}
+	history[key] = statements
+}
+
+// Statements returns the history of the table, most recent first
+func (history StatementHistory) Statements(profile string, table string) []string {
+	return history[tableStateKey(profile, table)]
+}
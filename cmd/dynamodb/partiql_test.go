@@ -0,0 +1,77 @@
+package dynamodb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestStatementArguments(t *testing.T) {
+	statement := Statement{
+		Statement:  ` SELECT * FROM "orders" WHERE "pk" = ? AND "total" > ? `,
+		Parameters: `[{"S": "customer#1"}, {"N": "10"}]`,
+	}
+
+	arguments, err := statement.Arguments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		"--statement", `SELECT * FROM "orders" WHERE "pk" = ? AND "total" > ?`,
+		"--parameters", `[{"S":"customer#1"},{"N":"10"}]`,
+	}
+	if !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Got %v, expected %v", arguments, expected)
+	}
+}
+
+func TestStatementArgumentsErrors(t *testing.T) {
+	invalid := map[string]Statement{
+		"empty statement":     {Statement: "  "},
+		"parameters not list": {Statement: `SELECT * FROM "t" WHERE "pk" = ?`, Parameters: `{"S": "a"}`},
+		"invalid parameter":   {Statement: `SELECT * FROM "t" WHERE "pk" = ?`, Parameters: `[{"N": "abc"}]`},
+		"unknown value type":  {Statement: `SELECT * FROM "t" WHERE "pk" = ?`, Parameters: `[{"X": "a"}]`},
+	}
+
+	for name, statement := range invalid {
+		if _, err := statement.Arguments(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestStatementHistory(t *testing.T) {
+	history := StatementHistory{}
+	history.Add("dev", "orders", "first")
+	history.Add("dev", "orders", "second")
+	history.Add("dev", "orders", "first")
+	history.Add("prod", "orders", "other")
+
+	expected := []string{"first", "second"}
+	if statements := history.Statements("dev", "orders"); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Got %v, expected %v", statements, expected)
+	}
+
+	for index := 0; index < StatementHistoryLimit+5; index++ {
+		history.Add("dev", "orders", fmt.Sprintf("statement %d", index))
+	}
+	if statements := history.Statements("dev", "orders"); len(statements) != StatementHistoryLimit {
+		t.Errorf("Expected %d statements, got %d", StatementHistoryLimit, len(statements))
+	}
+}
+
+func TestStatementIsRead(t *testing.T) {
+	tests := map[string]bool{
+		` select * FROM "orders"`:                true,
+		`SELECT"pk" FROM "orders"`:               true,
+		`DELETE FROM "orders" WHERE "pk" = 'a'`:  false,
+		"\n update \"orders\" SET total = 1":     false,
+		`INSERT INTO "orders" VALUE {'pk': 'a'}`: false,
+		`SELECTED`:                               false,
+	}
+	for text, expected := range tests {
+		if read := (Statement{Statement: text}).IsRead(); read != expected {
+			t.Errorf("Expected IsRead %v for %q, got %v", expected, text, read)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QueryState holds the key condition and filter of a scan or query, so they can be changed and run again
+type QueryState struct {
+	IndexName    string            // Secondary index being queried, empty for the table
+	KeyCondition Expression        // Key condition of a query, empty for scans
+	KeyValues    map[string]string // Values typed in the query form, kept to fill it again
+	Filter       Filter
+	Projection   []string // Attributes fetched with --projection-expression, all when empty
+	Columns      []string // Columns shown in the result table, all when empty
+
+	Reverse        bool // Descending sort key order (--no-scan-index-forward), queries only
+	ConsistentRead bool // Strongly consistent reads, not supported by global secondary indexes
+	PageSize       int  // Items per page (--limit), the configured limit when 0
+}
+
+// Arguments returns the AWS CLI arguments of the key condition and filter, sharing the placeholder maps
+func (state QueryState) Arguments() ([]string, error) {
+	filter, err := state.Filter.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	projection := state.projectionExpression()
+
+	names := map[string]string{}
+	values := map[string]AttributeValue{}
+	owners := map[string]string{}
+	expressions := []struct {
+		source     string
+		expression Expression
+	}{{"key condition", state.KeyCondition}, {"filter", filter}, {"projection", projection}}
+	for _, expression := range expressions {
+		if err := mergePlaceholders(names, owners, expression.source, expression.expression.Names); err != nil {
+			return nil, err
+		}
+		if err := mergePlaceholders(values, owners, expression.source, expression.expression.Values); err != nil {
+			return nil, err
+		}
+	}
+
+	var arguments []string
+	if state.KeyCondition.Expression != "" {
+		arguments = append(arguments, "--key-condition-expression", state.KeyCondition.Expression)
+	}
+	arguments = append(arguments, filter.Arguments()...)
+	if projection.Expression != "" {
+		arguments = append(arguments, "--projection-expression", projection.Expression)
+	}
+
+	if len(names) > 0 {
+		namesJson, err := json.Marshal(names)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, "--expression-attribute-names", string(namesJson))
+	}
+	if len(values) > 0 {
+		valuesJson, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, "--expression-attribute-values", string(valuesJson))
+	}
+
+	if state.IndexName != "" {
+		arguments = append(arguments, "--index-name", state.IndexName)
+	}
+
+	if state.Reverse {
+		if state.KeyCondition.Expression == "" {
+			return nil, fmt.Errorf("the reverse order is only available for queries")
+		}
+		arguments = append(arguments, "--no-scan-index-forward")
+	}
+	if state.ConsistentRead {
+		arguments = append(arguments, "--consistent-read")
+	}
+	if state.PageSize < 0 {
+		return nil, fmt.Errorf("the page size must be positive")
+	}
+	if state.PageSize > 0 {
+		arguments = append(arguments, "--limit", strconv.Itoa(state.PageSize))
+	}
+
+	return arguments, nil
+}
+
+// ReadOptions describes the order, consistency and page size differing from the defaults, empty when none does
+func (state QueryState) ReadOptions() string {
+	var options []string
+	if state.Reverse {
+		options = append(options, "descending")
+	}
+	if state.ConsistentRead {
+		options = append(options, "consistent read")
+	}
+	if state.PageSize > 0 {
+		options = append(options, fmt.Sprintf("%d per page", state.PageSize))
+	}
+	return strings.Join(options, ", ")
+}
+
+// projectionExpression returns the projection with a #p<n> name placeholder per attribute
+func (state QueryState) projectionExpression() Expression {
+	expression := Expression{Names: map[string]string{}}
+	var placeholders []string
+	for index, attribute := range state.Projection {
+		placeholder := fmt.Sprintf("#p%d", index)
+		expression.Names[placeholder] = attribute
+		placeholders = append(placeholders, placeholder)
+	}
+	expression.Expression = strings.Join(placeholders, ", ")
+	return expression
+}
+
+// mergePlaceholders copies the placeholders of source into target, failing when a placeholder is already used for something else.
+// owners records the source of every placeholder, naming both sides of a clash.
+func mergePlaceholders[T any](target map[string]T, owners map[string]string, source string, placeholders map[string]T) error {
+	for placeholder, value := range placeholders {
+		if existing, exists := target[placeholder]; exists {
+			existingJson, _ := json.Marshal(existing)
+			valueJson, _ := json.Marshal(value)
+			if string(existingJson) != string(valueJson) {
+				return fmt.Errorf("placeholder %s is used by both the %s and the %s", placeholder, owners[placeholder], source)
+			}
+			continue
+		}
+		target[placeholder] = value
+		owners[placeholder] = source
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueryStateArguments(t *testing.T) {
+	status, _ := NewAttributeValue("S", "active")
+	state := QueryState{
+		IndexName: "byStatus",
+		KeyCondition: Expression{
+			Expression: "#key0 = :val0",
+			Names:      map[string]string{"#key0": "status"},
+			Values:     map[string]AttributeValue{":val0": status},
+		},
+		Filter: Filter{Conditions: []FilterCondition{{Attribute: "age", Operator: FilterGreater, Type: "N", Value: "30"}}},
+	}
+
+	arguments, err := state.Arguments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"--key-condition-expression", "#key0 = :val0",
+		"--filter-expression", "#f0 > :f0",
+		"--expression-attribute-names", `{"#f0":"age","#key0":"status"}`,
+		"--expression-attribute-values", `{":f0":{"N":"30"},":val0":{"S":"active"}}`,
+		"--index-name", "byStatus",
+	}
+	if !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Got %v, expected %v", arguments, expected)
+	}
+}
+
+func TestQueryStateArgumentsWithoutExpressions(t *testing.T) {
+	arguments, err := QueryState{}.Arguments()
+	if err != nil || len(arguments) != 0 {
+		t.Errorf("Expected no arguments, got %v (%v)", arguments, err)
+	}
+}
+
+func TestQueryStateArgumentsWithProjection(t *testing.T) {
+	arguments, err := QueryState{Projection: []string{"pk", "name"}}.Arguments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"--projection-expression", "#p0, #p1",
+		"--expression-attribute-names", `{"#p0":"pk","#p1":"name"}`,
+	}
+	if !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Got %v, expected %v", arguments, expected)
+	}
+}
+
+func TestQueryStateArgumentsWithReadOptions(t *testing.T) {
+	pk, _ := NewAttributeValue("S", "user#1")
+	state := QueryState{
+		KeyCondition: Expression{
+			Expression: "#key0 = :val0",
+			Names:      map[string]string{"#key0": "pk"},
+			Values:     map[string]AttributeValue{":val0": pk},
+		},
+		Reverse:        true,
+		ConsistentRead: true,
+		PageSize:       100,
+	}
+
+	arguments, err := state.Arguments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"--key-condition-expression", "#key0 = :val0",
+		"--expression-attribute-names", `{"#key0":"pk"}`,
+		"--expression-attribute-values", `{":val0":{"S":"user#1"}}`,
+		"--no-scan-index-forward",
+		"--consistent-read",
+		"--limit", "100",
+	}
+	if !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Got %v, expected %v", arguments, expected)
+	}
+	if options := state.ReadOptions(); options != "descending, consistent read, 100 per page" {
+		t.Errorf("Unexpected read options %q", options)
+	}
+}
+
+func TestQueryStateArgumentsRejectsInvalidReadOptions(t *testing.T) {
+	if _, err := (QueryState{Reverse: true}).Arguments(); err == nil {
+		t.Error("Expected an error for a reversed scan")
+	}
+	if _, err := (QueryState{PageSize: -1}).Arguments(); err == nil {
+		t.Error("Expected an error for a negative page size")
+	}
+	if options := (QueryState{}).ReadOptions(); options != "" {
+		t.Errorf("Expected no read options, got %q", options)
+	}
+}
+
+func TestQueryStateArgumentsRejectsPlaceholderClash(t *testing.T) {
+	state := QueryState{
+		Filter:     Filter{RawExpression: "#p0 = :v", RawNames: `{"#p0": "other"}`, RawValues: `{":v": {"S": "x"}}`},
+		Projection: []string{"pk"},
+	}
+	if _, err := state.Arguments(); err == nil || !strings.Contains(err.Error(), "the filter and the projection") {
+		t.Errorf("Expected a clash between the filter and the projection, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SavedQuery is a query kept under a name, with the form values it is filled with again
+type SavedQuery struct {
+	Name           string            `yaml:"name"`
+	IndexName      string            `yaml:"index,omitempty"` // Secondary index, empty for the table keys
+	KeyValues      map[string]string `yaml:"keyValues"`
+	Filter         Filter            `yaml:"filter,omitempty"`
+	Projection     []string          `yaml:"projection,omitempty"`
+	Columns        []string          `yaml:"columns,omitempty"`
+	Reverse        bool              `yaml:"reverse,omitempty"`
+	ConsistentRead bool              `yaml:"consistentRead,omitempty"`
+	PageSize       int               `yaml:"pageSize,omitempty"`
+}
+
+// NewSavedQuery keeps the index, form values, filter, projection and read options of the state under the name
+func NewSavedQuery(name string, state QueryState) SavedQuery {
+	return SavedQuery{
+		Name:           strings.TrimSpace(name),
+		IndexName:      state.IndexName,
+		KeyValues:      state.KeyValues,
+		Filter:         state.Filter,
+		Projection:     state.Projection,
+		Columns:        state.Columns,
+		Reverse:        state.Reverse,
+		ConsistentRead: state.ConsistentRead,
+		PageSize:       state.PageSize,
+	}
+}
+
+// State returns the query state of the saved query, its key condition is built again from the form values
+func (query SavedQuery) State() QueryState {
+	return QueryState{
+		IndexName:      query.IndexName,
+		KeyValues:      query.KeyValues,
+		Filter:         query.Filter,
+		Projection:     query.Projection,
+		Columns:        query.Columns,
+		Reverse:        query.Reverse,
+		ConsistentRead: query.ConsistentRead,
+		PageSize:       query.PageSize,
+	}
+}
+
+// SavedQueries holds the saved queries per profile and table, sorted by name
+type SavedQueries map[string][]SavedQuery
+
+// Save adds the query to the table, replacing a saved query with the same name
+func (saved SavedQueries) Save(profile string, table string, query SavedQuery) error {
+	if query.Name == "" {
+		return fmt.Errorf("set a name for the query")
+	}
+	key := tableStateKey(profile, table)
+	queries := []SavedQuery{query}
+	for _, previous := range saved[key] {
+		if previous.Name != query.Name {
+			queries = append(queries, previous)
+		}
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	saved[key] = queries
+	return nil
+}
+
+// Delete removes the named query of the table, reporting whether it existed
+func (saved SavedQueries) Delete(profile string, table string, name string) bool {
+	key := tableStateKey(profile, table)
+	for index, query := range saved[key] {
+		if query.Name == name {
+			saved[key] = append(saved[key][:index:index], saved[key][index+1:]...)
+			if len(saved[key]) == 0 {
+				delete(saved, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Queries returns the saved queries of the table, sorted by name
+func (saved SavedQueries) Queries(profile string, table string) []SavedQuery {
+	return saved[tableStateKey(profile, table)]
+}
+
+// Find returns the named query of the table
+func (saved SavedQueries) Find(profile string, table string, name string) (SavedQuery, bool) {
+	for _, query := range saved.Queries(profile, table) {
+		if query.Name == name {
+			return query, true
+		}
+	}
+	return SavedQuery{}, false
+}
@@ -0,0 +1,68 @@
+package dynamodb
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestSavedQueries(t *testing.T) {
+	saved := SavedQueries{}
+	if err := saved.Save("dev", "orders", SavedQuery{}); err == nil {
+		t.Error("Expected an error for a query without name")
+	}
+
+	saved.Save("dev", "orders", SavedQuery{Name: "recent", KeyValues: map[string]string{"pk": "1"}})
+	saved.Save("dev", "orders", SavedQuery{Name: "by customer", IndexName: "GSI1"})
+	saved.Save("dev", "orders", SavedQuery{Name: "recent", KeyValues: map[string]string{"pk": "2"}})
+	saved.Save("prod", "orders", SavedQuery{Name: "recent"})
+
+	queries := saved.Queries("dev", "orders")
+	if len(queries) != 2 || queries[0].Name != "by customer" || queries[1].Name != "recent" {
+		t.Fatalf("Expected the queries sorted by name, got %+v", queries)
+	}
+	if query, ok := saved.Find("dev", "orders", "recent"); !ok || query.KeyValues["pk"] != "2" {
+		t.Errorf("Expected the replaced query, got %+v", query)
+	}
+
+	if !saved.Delete("dev", "orders", "recent") || saved.Delete("dev", "orders", "recent") {
+		t.Error("Expected the query to be deleted once")
+	}
+	if _, ok := saved.Find("dev", "orders", "recent"); ok {
+		t.Error("Expected the deleted query to be gone")
+	}
+	if len(saved.Queries("prod", "orders")) != 1 {
+		t.Error("Expected the queries of other profiles to be kept")
+	}
+}
+
+func TestSavedQueryState(t *testing.T) {
+	state := QueryState{
+		IndexName:      "GSI1",
+		KeyValues:      map[string]string{"customerId": "C-42"},
+		Filter:         Filter{Conditions: []FilterCondition{{Attribute: "status", Operator: FilterEqual, Type: "S", Value: "open"}}},
+		Projection:     []string{"pk", "total"},
+		Reverse:        true,
+		ConsistentRead: false,
+		PageSize:       10,
+	}
+
+	// The saved queries go through the YAML local state file
+	content, err := yaml.Marshal(SavedQueries{"dev/orders": {NewSavedQuery(" open orders ", state)}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded := SavedQueries{}
+	if err := yaml.Unmarshal(content, &loaded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query, ok := loaded.Find("dev", "orders", "open orders")
+	if !ok {
+		t.Fatalf("Expected the saved query, got %s", content)
+	}
+	if !reflect.DeepEqual(query.State(), state) {
+		t.Errorf("Got %+v, expected %+v", query.State(), state)
+	}
+}
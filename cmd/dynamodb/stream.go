@@ -0,0 +1,262 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StreamsService is the AWS CLI service reading DynamoDB Streams
+const StreamsService = "dynamodbstreams"
+
+// Shard iterator types of get-shard-iterator
+const (
+	IteratorTrimHorizon         = "TRIM_HORIZON"
+	IteratorLatest              = "LATEST"
+	IteratorAtSequenceNumber    = "AT_SEQUENCE_NUMBER"
+	IteratorAfterSequenceNumber = "AFTER_SEQUENCE_NUMBER"
+)
+
+var IteratorTypes = []string{IteratorTrimHorizon, IteratorLatest, IteratorAtSequenceNumber, IteratorAfterSequenceNumber}
+
+// StreamRecordColumns are the columns of the stream records table, the JSON viewer shows the images and their diff
+var StreamRecordColumns = []string{"ApproximateCreationDateTime", "eventName", "Keys", "Changed", "SequenceNumber"}
+
+// Shard is a shard of a stream, it is closed once it has an ending sequence number
+type Shard struct {
+	ShardId             string `json:"ShardId"`
+	ParentShardId       string `json:"ParentShardId"`
+	SequenceNumberRange struct {
+		StartingSequenceNumber string `json:"StartingSequenceNumber"`
+		EndingSequenceNumber   string `json:"EndingSequenceNumber"`
+	} `json:"SequenceNumberRange"`
+}
+
+// IsOpen reports whether the shard still receives records
+func (shard Shard) IsOpen() bool {
+	return shard.SequenceNumberRange.EndingSequenceNumber == ""
+}
+
+// Label describes the shard in a list, e.g. "shardId-0001 (open)"
+func (shard Shard) Label() string {
+	if shard.IsOpen() {
+		return fmt.Sprintf("%s (open)", shard.ShardId)
+	}
+	return fmt.Sprintf("%s (closed)", shard.ShardId)
+}
+
+// StreamDescription is the StreamDescription of the describe-stream output
+type StreamDescription struct {
+	StreamArn            string  `json:"StreamArn"`
+	StreamLabel          string  `json:"StreamLabel"`
+	StreamStatus         string  `json:"StreamStatus"`
+	StreamViewType       string  `json:"StreamViewType"`
+	TableName            string  `json:"TableName"`
+	Shards               []Shard `json:"Shards"`
+	LastEvaluatedShardId string  `json:"LastEvaluatedShardId"`
+}
+
+// ParseStreamDescription decodes one page of the describe-stream output
+func ParseStreamDescription(output string) (StreamDescription, error) {
+	var result struct {
+		StreamDescription *StreamDescription `json:"StreamDescription"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return StreamDescription{}, err
+	}
+	if result.StreamDescription == nil {
+		return StreamDescription{}, fmt.Errorf("describe-stream output has no StreamDescription attribute")
+	}
+	return *result.StreamDescription, nil
+}
+
+// DescribeStream describes the stream with all its shards, following LastEvaluatedShardId.
+// runPage runs describe-stream from startShardId, empty for the first page.
+func DescribeStream(runPage func(startShardId string) (string, error)) (StreamDescription, error) {
+	var stream StreamDescription
+	startShardId := ""
+	for {
+		output, err := runPage(startShardId)
+		if err != nil {
+			return StreamDescription{}, err
+		}
+		page, err := ParseStreamDescription(output)
+		if err != nil {
+			return StreamDescription{}, err
+		}
+
+		shards := append(stream.Shards, page.Shards...)
+		stream = page
+		stream.Shards = shards
+		if page.LastEvaluatedShardId == "" || page.LastEvaluatedShardId == startShardId {
+			stream.LastEvaluatedShardId = ""
+			return stream, nil
+		}
+		startShardId = page.LastEvaluatedShardId
+	}
+}
+
+// StreamPosition is where the records of a stream are read from
+type StreamPosition struct {
+	ShardId        string
+	IteratorType   string // One of IteratorTypes
+	SequenceNumber string // Set for AT_SEQUENCE_NUMBER and AFTER_SEQUENCE_NUMBER only
+}
+
+// ShardIteratorArguments returns the get-shard-iterator arguments of the position
+func (position StreamPosition) ShardIteratorArguments(streamArn string) ([]string, error) {
+	if position.ShardId == "" {
+		return nil, fmt.Errorf("choose a shard")
+	}
+	arguments := []string{"--stream-arn", streamArn, "--shard-id", position.ShardId, "--shard-iterator-type", position.IteratorType}
+
+	switch position.IteratorType {
+	case IteratorTrimHorizon, IteratorLatest:
+		return arguments, nil
+	case IteratorAtSequenceNumber, IteratorAfterSequenceNumber:
+		sequenceNumber := strings.TrimSpace(position.SequenceNumber)
+		if sequenceNumber == "" {
+			return nil, fmt.Errorf("%s requires a sequence number", position.IteratorType)
+		}
+		return append(arguments, "--sequence-number", sequenceNumber), nil
+	default:
+		return nil, fmt.Errorf("unknown shard iterator type %q", position.IteratorType)
+	}
+}
+
+// ParseShardIterator returns the iterator of the get-shard-iterator output
+func ParseShardIterator(output string) (string, error) {
+	var result struct {
+		ShardIterator string `json:"ShardIterator"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return "", err
+	}
+	if result.ShardIterator == "" {
+		return "", fmt.Errorf("get-shard-iterator output has no ShardIterator attribute")
+	}
+	return result.ShardIterator, nil
+}
+
+// AttributeChange is the old and new value of an attribute changed by a stream record, nil when it did not exist
+type AttributeChange struct {
+	Old interface{} `json:"Old"`
+	New interface{} `json:"New"`
+}
+
+// DiffImages returns the attributes that differ between the old and new image, with plain values
+func DiffImages(oldImage Item, newImage Item) map[string]AttributeChange {
+	diff := map[string]AttributeChange{}
+	for name, oldValue := range oldImage {
+		newValue, exists := newImage[name]
+		if !exists {
+			diff[name] = AttributeChange{Old: oldValue.Plain()}
+		} else if !oldValue.Equal(newValue) {
+			diff[name] = AttributeChange{Old: oldValue.Plain(), New: newValue.Plain()}
+		}
+	}
+	for name, newValue := range newImage {
+		if _, exists := oldImage[name]; !exists {
+			diff[name] = AttributeChange{New: newValue.Plain()}
+		}
+	}
+	return diff
+}
+
+// streamRecordRow is a stream record as shown by the records table and the JSON viewer
+type streamRecordRow struct {
+	ApproximateCreationDateTime Timestamp                  `json:"ApproximateCreationDateTime"`
+	EventName                   string                     `json:"eventName"`
+	Keys                        string                     `json:"Keys"`
+	Changed                     string                     `json:"Changed"`
+	SequenceNumber              string                     `json:"SequenceNumber"`
+	Diff                        map[string]AttributeChange `json:"Diff,omitempty"`
+	NewImage                    map[string]interface{}     `json:"NewImage,omitempty"`
+	OldImage                    map[string]interface{}     `json:"OldImage,omitempty"`
+	SizeBytes                   int64                      `json:"SizeBytes"`
+	EventID                     string                     `json:"eventID"`
+	UserIdentity                interface{}                `json:"userIdentity,omitempty"` // Set for deletions by the time to live
+}
+
+// StreamRecordsView rewrites a get-records output with one row per record: its event, keys, changed attributes
+// and plain images, keeping NextShardIterator for the pagination
+func StreamRecordsView(output string) (string, error) {
+	var result struct {
+		Records []struct {
+			EventID      string      `json:"eventID"`
+			EventName    string      `json:"eventName"`
+			UserIdentity interface{} `json:"userIdentity"`
+			Dynamodb     struct {
+				ApproximateCreationDateTime Timestamp `json:"ApproximateCreationDateTime"`
+				Keys                        Item      `json:"Keys"`
+				NewImage                    Item      `json:"NewImage"`
+				OldImage                    Item      `json:"OldImage"`
+				SequenceNumber              string    `json:"SequenceNumber"`
+				SizeBytes                   int64     `json:"SizeBytes"`
+			} `json:"dynamodb"`
+		} `json:"Records"`
+		NextShardIterator string `json:"NextShardIterator,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return "", fmt.Errorf("unexpected get-records output: %w", err)
+	}
+
+	rows := []streamRecordRow{}
+	for _, record := range result.Records {
+		row := streamRecordRow{
+			ApproximateCreationDateTime: record.Dynamodb.ApproximateCreationDateTime,
+			EventName:                   record.EventName,
+			Keys:                        keysText(record.Dynamodb.Keys),
+			SequenceNumber:              record.Dynamodb.SequenceNumber,
+			SizeBytes:                   record.Dynamodb.SizeBytes,
+			EventID:                     record.EventID,
+			UserIdentity:                record.UserIdentity,
+		}
+		if record.Dynamodb.NewImage != nil {
+			row.NewImage = record.Dynamodb.NewImage.Plain()
+		}
+		if record.Dynamodb.OldImage != nil {
+			row.OldImage = record.Dynamodb.OldImage.Plain()
+		}
+		// Inserts and removals have one image only, their diff would list every attribute
+		if record.Dynamodb.NewImage != nil && record.Dynamodb.OldImage != nil {
+			row.Diff = DiffImages(record.Dynamodb.OldImage, record.Dynamodb.NewImage)
+			row.Changed = changedText(row.Diff, record.Dynamodb.OldImage, record.Dynamodb.NewImage)
+		}
+		rows = append(rows, row)
+	}
+
+	view, err := json.Marshal(struct {
+		Records           []streamRecordRow `json:"Records"`
+		NextShardIterator string            `json:"NextShardIterator,omitempty"`
+	}{rows, result.NextShardIterator})
+	return string(view), err
+}
+
+// keysText describes the key of a record, e.g. "pk=user#1, sk=2024"
+func keysText(keys Item) string {
+	var parts []string
+	for _, name := range keys.names() {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, csvValue(keys[name])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changedText lists the changed attributes, "+" marks added and "-" removed ones, e.g. "+note, status"
+func changedText(diff map[string]AttributeChange, oldImage Item, newImage Item) string {
+	names := make([]string, 0, len(diff))
+	for name := range diff {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for index, name := range names {
+		if _, exists := oldImage[name]; !exists {
+			names[index] = "+" + name
+		} else if _, exists := newImage[name]; !exists {
+			names[index] = "-" + name
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,115 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeStream(t *testing.T) {
+	var startShardIds []string
+	stream, err := DescribeStream(func(startShardId string) (string, error) {
+		startShardIds = append(startShardIds, startShardId)
+		if startShardId == "" {
+			return `{"StreamDescription": {"StreamArn": "arn:stream", "StreamViewType": "NEW_AND_OLD_IMAGES",
+				"Shards": [{"ShardId": "shard-1", "SequenceNumberRange": {"StartingSequenceNumber": "100", "EndingSequenceNumber": "200"}}],
+				"LastEvaluatedShardId": "shard-1"}}`, nil
+		}
+		return `{"StreamDescription": {"StreamArn": "arn:stream", "StreamViewType": "NEW_AND_OLD_IMAGES",
+			"Shards": [{"ShardId": "shard-2", "ParentShardId": "shard-1", "SequenceNumberRange": {"StartingSequenceNumber": "300"}}]}}`, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(startShardIds) != "[ shard-1]" {
+		t.Errorf("Unexpected start shard ids %v", startShardIds)
+	}
+	if len(stream.Shards) != 2 || stream.StreamViewType != "NEW_AND_OLD_IMAGES" || stream.LastEvaluatedShardId != "" {
+		t.Fatalf("Unexpected stream %+v", stream)
+	}
+	if stream.Shards[0].Label() != "shard-1 (closed)" || stream.Shards[1].Label() != "shard-2 (open)" {
+		t.Errorf("Unexpected shard labels %s, %s", stream.Shards[0].Label(), stream.Shards[1].Label())
+	}
+}
+
+func TestShardIteratorArguments(t *testing.T) {
+	arguments, err := StreamPosition{ShardId: "shard-1", IteratorType: IteratorAfterSequenceNumber, SequenceNumber: " 42 "}.ShardIteratorArguments("arn:stream")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"--stream-arn", "arn:stream", "--shard-id", "shard-1", "--shard-iterator-type", "AFTER_SEQUENCE_NUMBER", "--sequence-number", "42"}
+	if !reflect.DeepEqual(arguments, expected) {
+		t.Errorf("Got %v, expected %v", arguments, expected)
+	}
+
+	for _, position := range []StreamPosition{
+		{IteratorType: IteratorLatest},
+		{ShardId: "shard-1", IteratorType: IteratorAtSequenceNumber},
+		{ShardId: "shard-1", IteratorType: "AT_TIMESTAMP"},
+	} {
+		if _, err := position.ShardIteratorArguments("arn:stream"); err == nil {
+			t.Errorf("Expected an error for %+v", position)
+		}
+	}
+
+	if iterator, err := ParseShardIterator(`{"ShardIterator": "iterator-1"}`); err != nil || iterator != "iterator-1" {
+		t.Errorf("Unexpected iterator %q (%v)", iterator, err)
+	}
+}
+
+func TestStreamRecordsView(t *testing.T) {
+	output := `{"Records": [
+		{"eventID": "1", "eventName": "INSERT", "dynamodb": {"ApproximateCreationDateTime": 1700000000, "SequenceNumber": "100", "SizeBytes": 20,
+			"Keys": {"pk": {"S": "user#1"}, "sk": {"N": "2"}}, "NewImage": {"pk": {"S": "user#1"}, "sk": {"N": "2"}, "status": {"S": "open"}}}},
+		{"eventID": "2", "eventName": "MODIFY", "dynamodb": {"ApproximateCreationDateTime": 1700000060, "SequenceNumber": "200", "SizeBytes": 40,
+			"Keys": {"pk": {"S": "user#1"}, "sk": {"N": "2"}},
+			"OldImage": {"pk": {"S": "user#1"}, "sk": {"N": "2"}, "status": {"S": "open"}, "legacy": {"BOOL": true}},
+			"NewImage": {"pk": {"S": "user#1"}, "sk": {"N": "2"}, "status": {"S": "closed"}, "note": {"NULL": true}}}}
+	], "NextShardIterator": "iterator-2"}`
+
+	view, err := StreamRecordsView(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result struct {
+		Records []struct {
+			ApproximateCreationDateTime string
+			EventName                   string `json:"eventName"`
+			Keys                        string
+			Changed                     string
+			Diff                        map[string]AttributeChange
+			NewImage                    map[string]interface{}
+			OldImage                    map[string]interface{}
+		}
+		NextShardIterator string
+	}
+	if err := json.Unmarshal([]byte(view), &result); err != nil {
+		t.Fatalf("Unexpected view %s: %v", view, err)
+	}
+	if len(result.Records) != 2 || result.NextShardIterator != "iterator-2" {
+		t.Fatalf("Unexpected view %s", view)
+	}
+
+	insert, modify := result.Records[0], result.Records[1]
+	if insert.EventName != "INSERT" || insert.Keys != "pk=user#1, sk=2" || insert.ApproximateCreationDateTime != "2023-11-14T22:13:20Z" {
+		t.Errorf("Unexpected insert row %+v", insert)
+	}
+	if insert.Diff != nil || insert.Changed != "" || insert.NewImage["status"] != "open" {
+		t.Errorf("Expected the new image without diff for an insert, got %+v", insert)
+	}
+
+	if modify.Changed != "-legacy, +note, status" {
+		t.Errorf("Unexpected changed attributes %q", modify.Changed)
+	}
+	expectedDiff := map[string]AttributeChange{
+		"status": {Old: "open", New: "closed"},
+		"legacy": {Old: true},
+		"note":   {},
+	}
+	if !reflect.DeepEqual(modify.Diff, expectedDiff) {
+		t.Errorf("Got diff %+v, expected %+v", modify.Diff, expectedDiff)
+	}
+}
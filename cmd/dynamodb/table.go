@@ -0,0 +1,157 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Key types of a key schema element
+const (
+	KeyTypeHash  = "HASH"
+	KeyTypeRange = "RANGE"
+)
+
+type KeySchemaElement struct {
+	AttributeName string `json:"AttributeName"`
+	KeyType       string `json:"KeyType"`
+}
+
+type AttributeDefinition struct {
+	AttributeName string `json:"AttributeName"`
+	AttributeType string `json:"AttributeType"`
+}
+
+type Projection struct {
+	ProjectionType   string   `json:"ProjectionType"`
+	NonKeyAttributes []string `json:"NonKeyAttributes"`
+}
+
+type ProvisionedThroughput struct {
+	ReadCapacityUnits  int64 `json:"ReadCapacityUnits"`
+	WriteCapacityUnits int64 `json:"WriteCapacityUnits"`
+}
+
+type SecondaryIndex struct {
+	IndexName             string                 `json:"IndexName"`
+	KeySchema             []KeySchemaElement     `json:"KeySchema"`
+	Projection            Projection             `json:"Projection"`
+	IndexStatus           string                 `json:"IndexStatus"`           // Global secondary indexes only
+	Backfilling           bool                   `json:"Backfilling"`           // Global secondary indexes only
+	ProvisionedThroughput *ProvisionedThroughput `json:"ProvisionedThroughput"` // Global secondary indexes only
+	ItemCount             int64                  `json:"ItemCount"`
+	IndexSizeBytes        int64                  `json:"IndexSizeBytes"`
+}
+
+type BillingModeSummary struct {
+	BillingMode string `json:"BillingMode"`
+}
+
+type StreamSpecification struct {
+	StreamEnabled  bool   `json:"StreamEnabled"`
+	StreamViewType string `json:"StreamViewType"`
+}
+
+type SSEDescription struct {
+	Status          string `json:"Status"`
+	SSEType         string `json:"SSEType"`
+	KMSMasterKeyArn string `json:"KMSMasterKeyArn"`
+}
+
+type TableClassSummary struct {
+	TableClass string `json:"TableClass"`
+}
+
+// TableDescription is the Table attribute of the describe-table output
+type TableDescription struct {
+	TableName              string                `json:"TableName"`
+	KeySchema              []KeySchemaElement    `json:"KeySchema"`
+	AttributeDefinitions   []AttributeDefinition `json:"AttributeDefinitions"`
+	GlobalSecondaryIndexes []SecondaryIndex      `json:"GlobalSecondaryIndexes"`
+	LocalSecondaryIndexes  []SecondaryIndex      `json:"LocalSecondaryIndexes"`
+
+	// Metadata shown by the table overview
+	TableArn                  string                 `json:"TableArn"`
+	TableStatus               string                 `json:"TableStatus"`
+	CreationDateTime          Timestamp              `json:"CreationDateTime"`
+	ItemCount                 int64                  `json:"ItemCount"`
+	TableSizeBytes            int64                  `json:"TableSizeBytes"`
+	BillingModeSummary        *BillingModeSummary    `json:"BillingModeSummary"`
+	ProvisionedThroughput     *ProvisionedThroughput `json:"ProvisionedThroughput"`
+	StreamSpecification       *StreamSpecification   `json:"StreamSpecification"`
+	LatestStreamArn           string                 `json:"LatestStreamArn"`
+	SSEDescription            *SSEDescription        `json:"SSEDescription"`
+	TableClassSummary         *TableClassSummary     `json:"TableClassSummary"`
+	DeletionProtectionEnabled bool                   `json:"DeletionProtectionEnabled"`
+}
+
+// ParseTableDescription decodes the output of describe-table
+func ParseTableDescription(output string) (TableDescription, error) {
+	var result struct {
+		Table *TableDescription `json:"Table"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return TableDescription{}, err
+	}
+	if result.Table == nil {
+		return TableDescription{}, fmt.Errorf("describe-table output has no Table attribute")
+	}
+	return *result.Table, nil
+}
+
+// KeyAttributes returns the table key attributes (partition then sort key) followed by the index keys
+func (table TableDescription) KeyAttributes() []string {
+	var attributes []string
+	seen := make(map[string]bool)
+	add := func(schema []KeySchemaElement) {
+		for _, keyType := range []string{KeyTypeHash, KeyTypeRange} {
+			for _, element := range schema {
+				if element.KeyType == keyType && !seen[element.AttributeName] {
+					seen[element.AttributeName] = true
+					attributes = append(attributes, element.AttributeName)
+				}
+			}
+		}
+	}
+
+	add(table.KeySchema)
+	for _, index := range table.GlobalSecondaryIndexes {
+		add(index.KeySchema)
+	}
+	for _, index := range table.LocalSecondaryIndexes {
+		add(index.KeySchema)
+	}
+	return attributes
+}
+
+// TableKeyAttributes returns the attributes of the table primary key, partition key first
+func (table TableDescription) TableKeyAttributes() []string {
+	var attributes []string
+	for _, keyType := range []string{KeyTypeHash, KeyTypeRange} {
+		for _, element := range table.KeySchema {
+			if element.KeyType == keyType {
+				attributes = append(attributes, element.AttributeName)
+			}
+		}
+	}
+	return attributes
+}
+
+// AttributeType returns the type (S, N or B) of a key attribute
+func (table TableDescription) AttributeType(name string) string {
+	for _, definition := range table.AttributeDefinitions {
+		if definition.AttributeName == name {
+			return definition.AttributeType
+		}
+	}
+	return ""
+}
+
+// IsGlobalIndex reports whether the name is a global secondary index of the table, which only supports eventually consistent reads
+func (table TableDescription) IsGlobalIndex(name string) bool {
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"reflect"
+	"testing"
+)
+
+const describeTableOutput = `{"Table": {
+	"TableName": "orders",
+	"AttributeDefinitions": [
+		{"AttributeName": "pk", "AttributeType": "S"},
+		{"AttributeName": "createdAt", "AttributeType": "N"},
+		{"AttributeName": "status", "AttributeType": "S"}
+	],
+	"KeySchema": [
+		{"AttributeName": "createdAt", "KeyType": "RANGE"},
+		{"AttributeName": "pk", "KeyType": "HASH"}
+	],
+	"GlobalSecondaryIndexes": [{
+		"IndexName": "byStatus",
+		"KeySchema": [{"AttributeName": "status", "KeyType": "HASH"}, {"AttributeName": "createdAt", "KeyType": "RANGE"}],
+		"Projection": {"ProjectionType": "KEYS_ONLY"},
+		"IndexStatus": "ACTIVE"
+	}]
+}}`
+
+func TestParseTableDescription(t *testing.T) {
+	table, err := ParseTableDescription(describeTableOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if keys := table.KeyAttributes(); !reflect.DeepEqual(keys, []string{"pk", "createdAt", "status"}) {
+		t.Errorf("Unexpected key attributes %v", keys)
+	}
+	if keys := table.TableKeyAttributes(); !reflect.DeepEqual(keys, []string{"pk", "createdAt"}) {
+		t.Errorf("Unexpected table key attributes %v", keys)
+	}
+	if attrType := table.AttributeType("createdAt"); attrType != "N" {
+		t.Errorf("Expected N, got %q", attrType)
+	}
+	if table.GlobalSecondaryIndexes[0].Projection.ProjectionType != "KEYS_ONLY" {
+		t.Errorf("Unexpected index %+v", table.GlobalSecondaryIndexes[0])
+	}
+}
+
+func TestParseTableDescriptionWithoutTable(t *testing.T) {
+	if _, err := ParseTableDescription(`{"TableNames": []}`); err == nil {
+		t.Errorf("Expected an error without Table attribute")
+	}
+}
@@ -0,0 +1,280 @@
+package genconfig
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+)
+
+// ResourceConfig mirrors cmd.Resource with empty fields left out of the generated YAML
+type ResourceConfig struct {
+	Name           string          `yaml:"name"`
+	DefaultCommand string          `yaml:"defaultCommand,omitempty"`
+	Commands       []CommandConfig `yaml:"commands"`
+}
+
+// CommandConfig mirrors cmd.Command with empty fields left out of the generated YAML
+type CommandConfig struct {
+	Name           string            `yaml:"name"`
+	DependsOn      string            `yaml:"depends_on,omitempty"`
+	ResourceName   string            `yaml:"resourceName,omitempty"`
+	Arguments      []string          `yaml:"arguments,omitempty"`
+	View           string            `yaml:"view"`
+	ShowJsonViewer bool              `yaml:"showJsonViewer,omitempty"`
+	Parse          cmd.Parse         `yaml:"parse"`
+	Pagination     *PaginationConfig `yaml:"pagination,omitempty"`
+}
+
+type PaginationConfig struct {
+	Enabled           bool   `yaml:"enabled"`
+	NextTokenParam    string `yaml:"nextTokenParam"`
+	NextTokenJsonPath string `yaml:"nextTokenJsonPath"`
+}
+
+// candidate is an operation that can become a command
+type candidate struct {
+	operationName string
+	command       CommandConfig
+	required      []string          // Required input members
+	provides      map[string]string // Identifiers the result rows can feed to other commands, with the row member holding them
+	parent        *candidate
+}
+
+var (
+	firstCapRegex   = regexp.MustCompile(`(.)([A-Z][a-z]+)`)
+	numberCapRegex  = regexp.MustCompile(`([a-z])([0-9]+)`)
+	endCapRegex     = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	identifierRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// cliName converts an operation or parameter name to its AWS CLI form, e.g. ListObjectsV2 -> list-objects-v2
+func cliName(name string) string {
+	name = firstCapRegex.ReplaceAllString(name, "${1}-${2}")
+	name = numberCapRegex.ReplaceAllString(name, "${1}-${2}")
+	return strings.ToLower(endCapRegex.ReplaceAllString(name, "${1}-${2}"))
+}
+
+// singular returns the singular form of a list attribute name, e.g. QueueUrls -> QueueUrl
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// Generate builds a resource configuration with the list and describe operations of the model.
+// Operations whose required parameters can not be filled by another command are skipped and reported.
+func Generate(service string, model ServiceModel, paginators map[string]Paginator) (ResourceConfig, []string) {
+	var skipped []string
+	var candidates []*candidate
+
+	operationNames := make([]string, 0, len(model.Operations))
+	for name := range model.Operations {
+		operationNames = append(operationNames, name)
+	}
+	sort.Strings(operationNames)
+
+	for _, operationName := range operationNames {
+		if !strings.HasPrefix(operationName, "List") && !strings.HasPrefix(operationName, "Describe") {
+			continue
+		}
+		if c, ok := newCandidate(operationName, model, paginators); ok {
+			candidates = append(candidates, c)
+		} else {
+			skipped = append(skipped, fmt.Sprintf("%s: output has no members to show", cliName(operationName)))
+		}
+	}
+
+	// Resolve dependencies until no more commands can be linked to a parent
+	var resolved []*candidate
+	pending := candidates
+	for {
+		var stillPending []*candidate
+		for _, c := range pending {
+			if len(c.required) == 0 {
+				resolved = append(resolved, c)
+				continue
+			}
+			if parent := findParent(c, resolved); parent != nil {
+				linkToParent(c, parent)
+				resolved = append(resolved, c)
+				continue
+			}
+			stillPending = append(stillPending, c)
+		}
+		if len(stillPending) == len(pending) {
+			pending = stillPending
+			break
+		}
+		pending = stillPending
+	}
+
+	for _, c := range pending {
+		skipped = append(skipped, fmt.Sprintf("%s: no command provides %s", c.command.Name, strings.Join(c.required, ", ")))
+	}
+
+	resource := ResourceConfig{Name: service}
+	for _, c := range resolved {
+		resource.Commands = append(resource.Commands, c.command)
+	}
+	sort.SliceStable(resource.Commands, func(i, j int) bool {
+		return resource.Commands[i].DependsOn == "" && resource.Commands[j].DependsOn != ""
+	})
+
+	for _, command := range resource.Commands {
+		if command.DependsOn == "" && command.ResourceName != "" {
+			resource.DefaultCommand = command.Name
+			break
+		}
+	}
+
+	return resource, skipped
+}
+
+// newCandidate describes how to parse and paginate an operation
+func newCandidate(operationName string, model ServiceModel, paginators map[string]Paginator) (*candidate, bool) {
+	operation := model.Operations[operationName]
+	c := &candidate{
+		operationName: operationName,
+		provides:      make(map[string]string),
+		command: CommandConfig{
+			Name: cliName(operationName),
+			View: "tableView",
+		},
+	}
+
+	if operation.Input != nil {
+		c.required = append(c.required, model.Shapes[operation.Input.Shape].Required...)
+	}
+
+	if operation.Output == nil {
+		return nil, false
+	}
+	output := model.Shapes[operation.Output.Shape]
+	if len(output.Members) == 0 {
+		return nil, false
+	}
+
+	paginator, paginated := paginators[operationName]
+	attributeName := chooseAttribute(output, model, paginator)
+	attribute, _ := output.Members.Get(attributeName)
+	attributeShape := model.Shapes[attribute.Shape]
+
+	c.command.Parse = cmd.Parse{Type: "object", AttributeName: attributeName}
+	if attributeShape.Type == "list" && attributeShape.Member != nil {
+		item := model.Shapes[attributeShape.Member.Shape]
+		if item.Type == "structure" {
+			// Rows are JSON objects, their first member identifies them
+			c.command.ShowJsonViewer = true
+			if len(item.Members) > 0 {
+				c.provides[singular(attributeName)] = item.Members[0].Name
+				c.provides[item.Members[0].Name] = item.Members[0].Name
+			}
+		} else {
+			c.provides[singular(attributeName)] = ""
+			c.command.Parse.Type = "list"
+		}
+	}
+
+	if paginated {
+		inputToken, inputOk := paginator.InputToken.(string)
+		outputToken, outputOk := paginator.OutputToken.(string)
+		if inputOk && outputOk && identifierRegex.MatchString(outputToken) {
+			// --no-paginate returns a single page together with its next token
+			c.command.Arguments = append(c.command.Arguments, "--no-paginate")
+			c.command.Pagination = &PaginationConfig{
+				Enabled:           true,
+				NextTokenParam:    "--" + cliName(inputToken),
+				NextTokenJsonPath: outputToken,
+			}
+		}
+	}
+
+	return c, true
+}
+
+// chooseAttribute picks the output member shown as table: the paginator result key, else the first list, else the first member
+func chooseAttribute(output Shape, model ServiceModel, paginator Paginator) string {
+	if resultKey, ok := paginator.ResultKey.(string); ok {
+		if _, exists := output.Members.Get(resultKey); exists {
+			return resultKey
+		}
+	}
+
+	for _, member := range output.Members {
+		if model.Shapes[member.Ref.Shape].Type == "list" {
+			return member.Name
+		}
+	}
+
+	return output.Members[0].Name
+}
+
+// findParent returns the first resolved command whose rows, or whose ancestors rows, provide every required parameter
+func findParent(c *candidate, resolved []*candidate) *candidate {
+	for _, parent := range resolved {
+		if parent.operationName == c.operationName || !providesAny(parent, c.required) {
+			continue
+		}
+
+		satisfied := true
+		for _, required := range c.required {
+			if providerOf(parent, required) == nil {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return parent
+		}
+	}
+	return nil
+}
+
+func providesAny(c *candidate, identifiers []string) bool {
+	for _, identifier := range identifiers {
+		if _, provided := c.provides[identifier]; provided {
+			return true
+		}
+	}
+	return false
+}
+
+// providerOf walks up from c and returns the command providing the identifier
+func providerOf(c *candidate, identifier string) *candidate {
+	for current := c; current != nil; current = current.parent {
+		if _, provided := current.provides[identifier]; provided {
+			return current
+		}
+	}
+	return nil
+}
+
+// linkToParent makes c depend on parent and fills required parameters with the providers placeholders.
+// Rows of structure lists are selected as JSON, so the placeholder is the path of the member, e.g. $FUNCTIONNAME.FunctionName
+func linkToParent(c *candidate, parent *candidate) {
+	c.parent = parent
+	c.command.DependsOn = parent.command.Name
+
+	var arguments []string
+	for _, required := range c.required {
+		provider := providerOf(parent, required)
+		if provider.command.ResourceName == "" {
+			provider.command.ResourceName = strings.ToLower(required[:1]) + required[1:]
+		}
+		placeholder := cmd.VariablePlaceHolderPrefix + strings.ToUpper(provider.command.ResourceName)
+		if member := provider.provides[required]; member != "" {
+			placeholder += "." + member
+		}
+		arguments = append(arguments, "--"+cliName(required), placeholder)
+	}
+	c.command.Arguments = append(arguments, c.command.Arguments...)
+}
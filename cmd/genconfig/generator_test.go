@@ -0,0 +1,110 @@
+package genconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestCliName(t *testing.T) {
+	tests := map[string]string{
+		"ListTables":              "list-tables",
+		"ListObjectsV2":           "list-objects-v2",
+		"DescribeTimeToLive":      "describe-time-to-live",
+		"ExclusiveStartTableName": "exclusive-start-table-name",
+		"QueueUrl":                "queue-url",
+	}
+	for name, expected := range tests {
+		if got := cliName(name); got != expected {
+			t.Errorf("cliName(%s) = %s, expected %s", name, got, expected)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	serviceDir, err := FindServiceDir([]string{"testdata"}, "dynamodb")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	model, paginators, err := LoadServiceModel(serviceDir)
+	if err != nil {
+		t.Fatalf("Unable to load model: %v", err)
+	}
+
+	resource, skipped := Generate("dynamodb", model, paginators)
+
+	if resource.DefaultCommand != "list-tables" {
+		t.Errorf("Expected list-tables as default command, got %q", resource.DefaultCommand)
+	}
+	if len(resource.Commands) != 3 {
+		t.Fatalf("Expected 3 commands, got %+v", resource.Commands)
+	}
+
+	listTables := resource.Commands[0]
+	if listTables.Name != "list-tables" || listTables.ResourceName != "tableName" || listTables.Parse.Type != "list" || listTables.Parse.AttributeName != "TableNames" {
+		t.Errorf("Unexpected list-tables command: %+v", listTables)
+	}
+	if listTables.Pagination == nil || listTables.Pagination.NextTokenParam != "--exclusive-start-table-name" || listTables.Pagination.NextTokenJsonPath != "LastEvaluatedTableName" {
+		t.Errorf("Unexpected list-tables pagination: %+v", listTables.Pagination)
+	}
+
+	describeTable := resource.Commands[1]
+	if describeTable.DependsOn != "list-tables" || !reflect.DeepEqual(describeTable.Arguments, []string{"--table-name", "$TABLENAME"}) || describeTable.Parse.AttributeName != "Table" {
+		t.Errorf("Unexpected describe-table command: %+v", describeTable)
+	}
+
+	if !reflect.DeepEqual(skipped, []string{"list-tags-of-resource: no command provides ResourceArn"}) {
+		t.Errorf("Unexpected skipped operations: %v", skipped)
+	}
+
+	// Parse settings left to their default are not written
+	content, err := yaml.Marshal(resource)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, field := range []string{"columnOrder", "valueFormat"} {
+		if strings.Contains(string(content), field) {
+			t.Errorf("Expected no %s in the generated configuration:\n%s", field, content)
+		}
+	}
+}
+
+func TestGenerateWithStructureList(t *testing.T) {
+	model := ServiceModel{
+		Operations: map[string]Operation{
+			"ListFunctions":    {Name: "ListFunctions", Output: &ShapeRef{Shape: "ListFunctionsResponse"}},
+			"DescribeFunction": {Name: "DescribeFunction", Input: &ShapeRef{Shape: "DescribeFunctionRequest"}, Output: &ShapeRef{Shape: "DescribeFunctionResponse"}},
+			"ListAliases":      {Name: "ListAliases", Input: &ShapeRef{Shape: "ListAliasesRequest"}, Output: &ShapeRef{Shape: "ListAliasesResponse"}},
+		},
+		Shapes: map[string]Shape{
+			"ListFunctionsResponse":    {Type: "structure", Members: Members{{Name: "Functions", Ref: ShapeRef{Shape: "FunctionList"}}}},
+			"FunctionList":             {Type: "list", Member: &ShapeRef{Shape: "FunctionConfiguration"}},
+			"FunctionConfiguration":    {Type: "structure", Members: Members{{Name: "FunctionName", Ref: ShapeRef{Shape: "String"}}, {Name: "Runtime", Ref: ShapeRef{Shape: "String"}}}},
+			"DescribeFunctionRequest":  {Type: "structure", Required: []string{"FunctionName"}, Members: Members{{Name: "FunctionName", Ref: ShapeRef{Shape: "String"}}}},
+			"DescribeFunctionResponse": {Type: "structure", Members: Members{{Name: "Configuration", Ref: ShapeRef{Shape: "FunctionConfiguration"}}}},
+			"ListAliasesRequest":       {Type: "structure", Required: []string{"Function"}, Members: Members{{Name: "Function", Ref: ShapeRef{Shape: "String"}}}},
+			"ListAliasesResponse":      {Type: "structure", Members: Members{{Name: "Aliases", Ref: ShapeRef{Shape: "AliasList"}}}},
+			"AliasList":                {Type: "list", Member: &ShapeRef{Shape: "String"}},
+			"String":                   {Type: "string"},
+		},
+	}
+
+	resource, skipped := Generate("lambda", model, nil)
+	if len(skipped) != 0 || len(resource.Commands) != 3 {
+		t.Fatalf("Unexpected commands %+v, skipped %v", resource.Commands, skipped)
+	}
+
+	// Rows of list-functions are JSON objects, dependent commands read the identifier from the selected row
+	arguments := map[string][]string{}
+	for _, command := range resource.Commands {
+		arguments[command.Name] = command.Arguments
+	}
+	if expected := []string{"--function-name", "$FUNCTIONNAME.FunctionName"}; !reflect.DeepEqual(arguments["describe-function"], expected) {
+		t.Errorf("Expected %v, got %v", expected, arguments["describe-function"])
+	}
+	if expected := []string{"--function", "$FUNCTIONNAME.FunctionName"}; !reflect.DeepEqual(arguments["list-aliases"], expected) {
+		t.Errorf("Expected %v, got %v", expected, arguments["list-aliases"])
+	}
+}
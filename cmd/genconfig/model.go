@@ -0,0 +1,199 @@
+package genconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/executor"
+)
+
+// ServiceModel is the subset of a botocore service-2.json model used to generate configurations
+type ServiceModel struct {
+	Metadata   ServiceMetadata      `json:"metadata"`
+	Operations map[string]Operation `json:"operations"`
+	Shapes     map[string]Shape     `json:"shapes"`
+}
+
+type ServiceMetadata struct {
+	EndpointPrefix string `json:"endpointPrefix"`
+	ServiceId      string `json:"serviceId"`
+}
+
+type Operation struct {
+	Name   string    `json:"name"`
+	Input  *ShapeRef `json:"input"`
+	Output *ShapeRef `json:"output"`
+}
+
+type ShapeRef struct {
+	Shape string `json:"shape"`
+}
+
+type Shape struct {
+	Type     string    `json:"type"`
+	Required []string  `json:"required"`
+	Members  Members   `json:"members"`
+	Member   *ShapeRef `json:"member"`
+}
+
+// Member is a named member of a structure shape
+type Member struct {
+	Name string
+	Ref  ShapeRef
+}
+
+// Members keeps structure members in model order, which is the order the AWS CLI prints them
+type Members []Member
+
+func (members *Members) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected member name %v", token)
+		}
+		member := Member{Name: name}
+		if err := decoder.Decode(&member.Ref); err != nil {
+			return err
+		}
+		*members = append(*members, member)
+	}
+	return nil
+}
+
+// Get returns the member with the given name
+func (members Members) Get(name string) (ShapeRef, bool) {
+	for _, member := range members {
+		if member.Name == name {
+			return member.Ref, true
+		}
+	}
+	return ShapeRef{}, false
+}
+
+// Paginator is a botocore paginators-1.json entry
+type Paginator struct {
+	InputToken  interface{} `json:"input_token"`
+	OutputToken interface{} `json:"output_token"`
+	LimitKey    string      `json:"limit_key"`
+	ResultKey   interface{} `json:"result_key"`
+}
+
+type paginatorsFile struct {
+	Pagination map[string]Paginator `json:"pagination"`
+}
+
+// ModelsDirCandidates returns the directories that may hold the botocore models of the local AWS CLI
+func ModelsDirCandidates() []string {
+	var candidates []string
+
+	// AWS CLI v2 bundles botocore next to its binary: <install>/v2/current/bin/aws -> <install>/v2/current/dist/awscli/botocore/data
+	if awsPath, err := exec.LookPath("aws"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(awsPath); err == nil {
+			installDir := filepath.Dir(filepath.Dir(resolved))
+			candidates = append(candidates,
+				filepath.Join(installDir, "dist", "awscli", "botocore", "data"),
+				filepath.Join(filepath.Dir(resolved), "awscli", "botocore", "data"),
+			)
+		}
+	}
+
+	candidates = append(candidates,
+		"/usr/local/aws-cli/v2/current/dist/awscli/botocore/data",
+		"/usr/local/aws-cli/aws-cli/v2/current/dist/awscli/botocore/data",
+	)
+
+	// AWS CLI v1 uses the botocore python package
+	if _, err := exec.LookPath("python3"); err == nil {
+		out := executor.ExecCommand("python3", []string{"-c", "import botocore, os; print(os.path.dirname(botocore.__file__))"})
+		if dir := strings.TrimSpace(out); filepath.IsAbs(dir) {
+			candidates = append(candidates, filepath.Join(dir, "data"))
+		}
+	}
+
+	return candidates
+}
+
+// FindServiceDir returns the directory holding the latest API version of the service model
+func FindServiceDir(modelsDirs []string, service string) (string, error) {
+	for _, modelsDir := range modelsDirs {
+		entries, err := os.ReadDir(filepath.Join(modelsDir, service))
+		if err != nil {
+			continue
+		}
+
+		var versions []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				versions = append(versions, entry.Name())
+			}
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		// API versions are dates (e.g. 2012-08-10), the last one is the latest
+		sort.Strings(versions)
+		return filepath.Join(modelsDir, service, versions[len(versions)-1]), nil
+	}
+
+	return "", fmt.Errorf("no model found for service %q in %s", service, strings.Join(modelsDirs, ", "))
+}
+
+// LoadServiceModel reads the service model and its paginators from a service version directory
+func LoadServiceModel(serviceDir string) (ServiceModel, map[string]Paginator, error) {
+	model := ServiceModel{}
+	if err := readModelFile(filepath.Join(serviceDir, "service-2.json"), &model); err != nil {
+		return model, nil, err
+	}
+
+	paginators := paginatorsFile{}
+	if err := readModelFile(filepath.Join(serviceDir, "paginators-1.json"), &paginators); err != nil && !os.IsNotExist(err) {
+		return model, nil, err
+	}
+
+	return model, paginators.Pagination, nil
+}
+
+// readModelFile decodes a model file, which newer AWS CLI releases ship gzipped
+func readModelFile(filename string, target interface{}) error {
+	content, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		content, err = os.ReadFile(filename + ".gz")
+		if err == nil {
+			content, err = gunzip(content)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", filename, err)
+	}
+	return nil
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -20,37 +21,73 @@ var ConfigurationsRelativeFileExtension = ".yaml"
 
 const VariablePlaceHolderPrefix = "$"
 
+// ProfilePlaceHolder is replaced with the selected profile name
+const ProfilePlaceHolder = VariablePlaceHolderPrefix + "PROFILE"
+
 var Resources = map[string]Resource{}
 
 type Command struct {
-	Name             string      `yaml:"name"`
-	ResourceName     string      `yaml:"resourceName"`
-	DefaultCommand   string      `yaml:"defaultCommand"`
-	DependsOn        string      `yaml:"depends_on"`
-	Arguments        []string    `yaml:"arguments"`
-	View             string      `yaml:"view"`
-	Parse            Parse       `yaml:"parse"`
-	ShowJsonViewer   bool        `yaml:"showJsonViewer"`
-	RerunOnBack      bool        `yaml:"rerunOnBack"`          // If true, rerun command when navigating back; if false, use cached result
-	RequiresKeyInput bool        `yaml:"requiresKeyInput"`     // If true, prompt user for key value before executing
-	Pagination       *Pagination `yaml:"pagination,omitempty"` // Pagination configuration
+	Name                   string      `yaml:"name"`
+	Action                 string      `yaml:"action"`  // AWS CLI command to run when it differs from the name (e.g. partiql runs execute-statement)
+	Service                string      `yaml:"service"` // AWS CLI service to run when it differs from the resource (e.g. dynamodbstreams)
+	ResourceName           string      `yaml:"resourceName"`
+	DefaultCommand         string      `yaml:"defaultCommand"`
+	DependsOn              string      `yaml:"depends_on"`
+	Arguments              []string    `yaml:"arguments"`
+	View                   string      `yaml:"view"`
+	Parse                  Parse       `yaml:"parse"`
+	ShowJsonViewer         bool        `yaml:"showJsonViewer"`
+	RerunOnBack            bool        `yaml:"rerunOnBack"`            // If true, rerun command when navigating back; if false, use cached result
+	RequiresKeyInput       bool        `yaml:"requiresKeyInput"`       // If true, prompt user for key value before executing
+	RequiresStatementInput bool        `yaml:"requiresStatementInput"` // If true, prompt user for a PartiQL statement before executing
+	RequiresStreamInput    bool        `yaml:"requiresStreamInput"`    // If true, prompt user for a DynamoDB stream shard and iterator before executing
+	Include                []string    `yaml:"include"`                // Named argument templates merged into the arguments
+	Binary                 string      `yaml:"binary"`                 // External executable to run instead of the AWS CLI (plugin command)
+	Pagination             *Pagination `yaml:"pagination,omitempty"`   // Pagination configuration
 }
 
 type Pagination struct {
 	Enabled           bool   `yaml:"enabled"`
+	Type              string `yaml:"type"`              // PaginationToken (default) or PaginationIterator
 	NextTokenParam    string `yaml:"nextTokenParam"`    // Parameter name for next token (e.g., "--starting-token" or "--exclusive-start-key")
 	NextTokenJsonPath string `yaml:"nextTokenJsonPath"` // JSON path to extract next token (e.g., "NextToken" or "LastEvaluatedKey")
+	MaxPages          int    `yaml:"maxPages"`          // Upper bound of pages followed by fetch-all (0 = DefaultFetchAllMaxPages)
+	MaxItems          int    `yaml:"maxItems"`          // Upper bound of items merged by fetch-all (0 = DefaultFetchAllMaxItems)
+}
+
+// Pagination types
+const (
+	PaginationToken = "token" // The first page runs without token, the next ones with the token of the previous page
+	// PaginationIterator runs every page with a token, the first one is obtained before the command runs (e.g. a shard iterator).
+	// Iterators never run out on open streams, so there is no fetch-all.
+	PaginationIterator = "iterator"
+)
+
+// IsIterator reports whether the pages are read with an iterator, see PaginationIterator
+func (pagination *Pagination) IsIterator() bool {
+	return pagination != nil && pagination.Type == PaginationIterator
 }
 
 type Parse struct {
-	Type          string `yaml:"type"`
-	AttributeName string `yaml:"attributeName"`
+	Type          string   `yaml:"type"`
+	AttributeName string   `yaml:"attributeName"`
+	ColumnOrder   string   `yaml:"columnOrder,omitempty"` // Order of object columns: first seen (default) or "alphabetical"
+	ValueFormat   string   `yaml:"valueFormat,omitempty"` // Format of cell values: JSON (default) or "dynamodb" typed values
+	KeyColumns    []string `yaml:"-"`                     // Columns shown first, set at runtime (e.g. DynamoDB key attributes)
+	Columns       []string `yaml:"-"`                     // When set, only these columns are shown
 }
 
+const (
+	ColumnOrderAlphabetical = "alphabetical" // Sorts object columns by name, after the key columns
+	ValueFormatDynamoDB     = "dynamodb"     // Unwraps DynamoDB typed values unless UiState.ShowDynamoDBJsonFormat is set
+)
+
 type Resource struct {
-	Name           string    `yaml:"name"`
-	DefaultCommand string    `yaml:"defaultCommand"`
-	Commands       []Command `yaml:"commands"`
+	Name           string              `yaml:"name"`
+	DefaultCommand string              `yaml:"defaultCommand"`
+	Defaults       []string            `yaml:"defaults"`  // Arguments appended to every command of the resource
+	Templates      map[string][]string `yaml:"templates"` // Named argument sets commands of the resource can include
+	Commands       []Command           `yaml:"commands"`
 }
 
 func Init() {
@@ -60,12 +97,24 @@ func Init() {
 		log.Fatal(err)
 	}
 
+	LoadErrors = nil
+	defaults, err := loadDefaults(fmt.Sprintf("%s/%s", ConfigurationsRelativeFilePath, DefaultsConfigurationFileName))
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Unable to load default arguments")
+		LoadErrors = append(LoadErrors, err)
+	}
+	GlobalDefaults = defaults
+
 	channel := make(chan Resource)
 
 	for _, e := range entries {
-		if filepath.Ext(e.Name()) == ConfigurationsRelativeFileExtension {
+		if filepath.Ext(e.Name()) == ConfigurationsRelativeFileExtension && e.Name() != DefaultsConfigurationFileName {
 			go processConfigurationFile(channel, fmt.Sprintf("%s/%s", ConfigurationsRelativeFilePath, e.Name()))
 			resource := <-channel
+			for _, expandErr := range expandResourceArguments(&resource, GlobalDefaults) {
+				logger.Logger.Error().Err(expandErr).Msg("Unable to expand command arguments")
+				LoadErrors = append(LoadErrors, expandErr)
+			}
 			Resources[resource.Name] = resource
 		}
 	}
@@ -108,11 +157,62 @@ func (command *Command) Run(resource string, profile string) string {
 }
 
 func (command *Command) RunWithPaginationToken(resource string, profile string, paginationToken string) string {
+	output, _ := command.RunWithContext(context.Background(), resource, profile, paginationToken)
+	return output
+}
+
+// RunWithContext runs the command with an optional pagination token, aborting when ctx is cancelled
+func (command *Command) RunWithContext(ctx context.Context, resource string, profile string, paginationToken string) (string, error) {
+	binaryName, args := command.CommandLine(resource, profile, paginationToken)
+
+	logger.Logger.Debug().Msg(fmt.Sprintf("Running: %s %s", binaryName, strings.Join(args, " ")))
+	start := time.Now()
+	output, err := executor.ExecCommandContext(ctx, binaryName, args)
+	logger.Logger.Debug().Msg(fmt.Sprintf("Execution time %s", time.Since(start)))
+
+	return output, err
+}
+
+// Execute runs the command like RunWithContext, failing when the command exits with an error (e.g. a rejected write)
+func (command *Command) Execute(ctx context.Context, resource string, profile string) (string, error) {
+	return command.ExecuteWithPaginationToken(ctx, resource, profile, "")
+}
+
+// ExecuteWithPaginationToken runs the command like Execute with an optional pagination token
+func (command *Command) ExecuteWithPaginationToken(ctx context.Context, resource string, profile string, paginationToken string) (string, error) {
+	binaryName, args := command.CommandLine(resource, profile, paginationToken)
+
+	logger.Logger.Debug().Msg(fmt.Sprintf("Running: %s %s", binaryName, strings.Join(args, " ")))
+	start := time.Now()
+	output, err := executor.ExecCommandWithError(ctx, binaryName, args)
+	logger.Logger.Debug().Msg(fmt.Sprintf("Execution time %s", time.Since(start)))
+
+	return output, err
+}
+
+// CommandLine returns the binary and the arguments used to run the command, with placeholders replaced
+func (command *Command) CommandLine(resource string, profile string, paginationToken string) (string, []string) {
 	binaryName := "aws"
 	var argumentsCopy = make([]string, len(command.Arguments))
 	copy(argumentsCopy, command.Arguments)
-	args := []string{resource, command.Name, "--profile", profile}
-	args = append(args, replaceVariablesOnCommandArguments(command.Arguments)...)
+	action := command.Name
+	if command.Action != "" {
+		action = command.Action
+	}
+	service := resource
+	if command.Service != "" {
+		service = command.Service
+	}
+	args := []string{service, action, "--profile", profile}
+
+	// Plugin commands run their own executable with the configured arguments only
+	if command.IsPlugin() {
+		binaryName = command.Binary
+		args = []string{}
+	}
+
+	// replace placeholders on a copy, so the original arguments keep their placeholders
+	args = append(args, replaceVariablesOnCommandArguments(argumentsCopy, profile)...)
 
 	// Add pagination token if provided and pagination is enabled
 	// Only add token parameter if both token and parameter name are non-empty
@@ -120,14 +220,7 @@ func (command *Command) RunWithPaginationToken(resource string, profile string,
 		args = append(args, command.Pagination.NextTokenParam, paginationToken)
 	}
 
-	logger.Logger.Debug().Msg(fmt.Sprintf("Running: %s %s", binaryName, strings.Join(args, " ")))
-	start := time.Now()
-	output := executor.ExecCommand(binaryName, args)
-	// set again original args which contains placeholders
-	copy(command.Arguments, argumentsCopy)
-	logger.Logger.Debug().Msg(fmt.Sprintf("Execution time %s", time.Since(start)))
-
-	return output
+	return binaryName, args
 }
 
 func processConfigurationFile(channel chan Resource, filename string) {
@@ -148,11 +241,15 @@ func processConfigurationFile(channel chan Resource, filename string) {
 	channel <- resource
 }
 
-func replaceVariablesOnCommandArguments(arguments []string) []string {
+// IsPlugin reports whether the command runs an external executable instead of the AWS CLI
+func (command *Command) IsPlugin() bool {
+	return command.Binary != ""
+}
+
+func replaceVariablesOnCommandArguments(arguments []string, profile string) []string {
 	for index, item := range arguments {
 		if strings.HasPrefix(item, VariablePlaceHolderPrefix) {
-			value, exists := UiState.SelectedItems[item]
-			if exists {
+			if value, exists := resolvePlaceholder(item, profile); exists {
 				arguments[index] = value
 			}
 		}
@@ -160,6 +257,54 @@ func replaceVariablesOnCommandArguments(arguments []string) []string {
 	return arguments
 }
 
+// resolvePlaceholder returns the value of a placeholder. Besides selected items, it supports:
+//   - $PROFILE: the selected profile name;
+//   - $ITEM.Path.To.Field: a field of a selected item holding JSON (e.g. a DynamoDB item or SQS message).
+func resolvePlaceholder(placeholder string, profile string) (string, bool) {
+	if value, exists := UiState.SelectedItems[placeholder]; exists {
+		return value, true
+	}
+
+	if placeholder == ProfilePlaceHolder {
+		return profile, true
+	}
+
+	parts := strings.Split(placeholder, ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	selectedValue, exists := UiState.SelectedItems[parts[0]]
+	if !exists {
+		return "", false
+	}
+
+	var current interface{}
+	if err := json.Unmarshal([]byte(selectedValue), &current); err != nil {
+		logger.Logger.Warn().Str("placeholder", placeholder).Msg("Selected item is not JSON, unable to resolve field")
+		return "", false
+	}
+
+	for _, field := range parts[1:] {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if current, ok = object[field]; !ok {
+			return "", false
+		}
+	}
+
+	if text, ok := current.(string); ok {
+		return text, true
+	}
+	bytes, err := json.Marshal(current)
+	if err != nil {
+		return "", false
+	}
+	return string(bytes), true
+}
+
 // ExtractPaginationToken extracts the next page token from JSON output
 func ExtractPaginationToken(jsonOutput string, command Command) string {
 	if command.Pagination == nil || !command.Pagination.Enabled {
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandLineForPluginCommand(t *testing.T) {
+	UiState.SelectedItems = map[string]string{
+		"$MESSAGE": `{"MessageId": "42", "Body": "{\"type\":\"created\"}", "Attributes": {"SenderId": "A1"}}`,
+	}
+	defer func() { UiState.SelectedItems = make(map[string]string) }()
+
+	command := Command{
+		Name:      "decode-envelope",
+		Binary:    "decode-envelope.sh",
+		Arguments: []string{"--profile", "$PROFILE", "--body", "$MESSAGE.Body", "--sender", "$MESSAGE.Attributes.SenderId", "$MESSAGE.Missing"},
+	}
+
+	binaryName, args := command.CommandLine("sqs", "localstack", "")
+
+	if binaryName != "decode-envelope.sh" {
+		t.Errorf("Expected plugin binary, got %s", binaryName)
+	}
+	expected := []string{"--profile", "localstack", "--body", `{"type":"created"}`, "--sender", "A1", "$MESSAGE.Missing"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Got %v, expected %v", args, expected)
+	}
+	if command.Arguments[3] != "$MESSAGE.Body" {
+		t.Errorf("Expected original arguments to keep their placeholders, got %v", command.Arguments)
+	}
+}
+
+func TestCommandLineForAwsCommand(t *testing.T) {
+	UiState.SelectedItems = map[string]string{"$TABLENAME": "simple_table"}
+	defer func() { UiState.SelectedItems = make(map[string]string) }()
+
+	command := Command{
+		Name:       "scan",
+		Arguments:  []string{"--table-name", "$TABLENAME"},
+		Pagination: &Pagination{Enabled: true, NextTokenParam: "--exclusive-start-key", NextTokenJsonPath: "LastEvaluatedKey"},
+	}
+
+	binaryName, args := command.CommandLine("dynamodb", "default", `{"id":{"S":"foo1"}}`)
+
+	expected := []string{"dynamodb", "scan", "--profile", "default", "--table-name", "simple_table", "--exclusive-start-key", `{"id":{"S":"foo1"}}`}
+	if binaryName != "aws" || !reflect.DeepEqual(args, expected) {
+		t.Errorf("Got %s %v, expected aws %v", binaryName, args, expected)
+	}
+}
+
+func TestCommandLineWithAction(t *testing.T) {
+	command := Command{Name: "partiql", Action: "execute-statement", Arguments: []string{"--statement", `SELECT * FROM "orders"`}}
+
+	_, args := command.CommandLine("dynamodb", "default", "")
+
+	expected := []string{"dynamodb", "execute-statement", "--profile", "default", "--statement", `SELECT * FROM "orders"`}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Got %v, expected %v", args, expected)
+	}
+}
+
+func TestCommandLineWithServiceAndIterator(t *testing.T) {
+	command := Command{
+		Name:       "streams",
+		Service:    "dynamodbstreams",
+		Action:     "get-records",
+		Arguments:  []string{"--limit", "100"},
+		Pagination: &Pagination{Enabled: true, Type: PaginationIterator, NextTokenParam: "--shard-iterator", NextTokenJsonPath: "NextShardIterator"},
+	}
+
+	_, args := command.CommandLine("dynamodb", "default", "iterator-1")
+
+	expected := []string{"dynamodbstreams", "get-records", "--profile", "default", "--limit", "100", "--shard-iterator", "iterator-1"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Got %v, expected %v", args, expected)
+	}
+	if command.SupportsFetchAll() {
+		t.Error("Expected no fetch-all for iterator pagination")
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LocalStateDirectory holds the files kept between sessions (e.g. the PartiQL history), <user config dir>/aws-commander when empty
+var LocalStateDirectory = ""
+
+func localStatePath(name string) (string, error) {
+	directory := LocalStateDirectory
+	if directory == "" {
+		configDirectory, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		directory = filepath.Join(configDirectory, "aws-commander")
+	}
+	return filepath.Join(directory, name+ConfigurationsRelativeFileExtension), nil
+}
+
+// LoadLocalState decodes the local state file with the given name into value, which is left unchanged when the file does not exist
+func LoadLocalState(name string, value interface{}) error {
+	path, err := localStatePath(name)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := yaml.Unmarshal(content, value); err != nil {
+		return fmt.Errorf("error while unmarshalling %s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveLocalState writes value to the local state file with the given name, readable by the current user only
+func SaveLocalState(name string, value interface{}) error {
+	path, err := localStatePath(name)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocalStateRoundTrip(t *testing.T) {
+	previous := LocalStateDirectory
+	defer func() { LocalStateDirectory = previous }()
+	LocalStateDirectory = t.TempDir() + "/nested"
+
+	var missing map[string][]string
+	if err := LoadLocalState("history", &missing); err != nil || missing != nil {
+		t.Fatalf("Expected nothing for a missing file, got %v, %v", missing, err)
+	}
+
+	state := map[string][]string{"default/orders": {"SELECT * FROM \"orders\"", "SELECT id FROM \"orders\""}}
+	if err := SaveLocalState("history", state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var loaded map[string][]string
+	if err := LoadLocalState("history", &loaded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("Got %v, expected %v", loaded, state)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/iancoleman/orderedmap"
+)
+
+const (
+	DefaultFetchAllMaxPages = 100
+	DefaultFetchAllMaxItems = 10000
+)
+
+// summableCountFields are top level counters that are added up when pages are merged
+var summableCountFields = []string{"Count", "ScannedCount", "KeyCount"}
+
+// FetchProgress reports the state of a running multi page fetch
+type FetchProgress struct {
+	Pages int
+	Items int
+}
+
+// FetchPagesResult holds the merged output of a multi page fetch
+type FetchPagesResult struct {
+	Output    string // Command output with the pages merged under Parse.AttributeName
+	Pages     int    // Number of pages fetched
+	Items     int    // Number of items merged
+	NextToken string // Token to continue from when a limit was reached or the fetch was cancelled
+	Cancelled bool   // True when ctx was cancelled before the last page
+}
+
+// FetchLimits returns the page and item limits configured for the command, falling back to defaults
+func (command *Command) FetchLimits() (int, int) {
+	maxPages, maxItems := DefaultFetchAllMaxPages, DefaultFetchAllMaxItems
+	if command.Pagination != nil {
+		if command.Pagination.MaxPages > 0 {
+			maxPages = command.Pagination.MaxPages
+		}
+		if command.Pagination.MaxItems > 0 {
+			maxItems = command.Pagination.MaxItems
+		}
+	}
+	return maxPages, maxItems
+}
+
+// SupportsFetchAll reports whether the command exposes a next token that can be followed automatically
+func (command *Command) SupportsFetchAll() bool {
+	return command.Pagination != nil && command.Pagination.Enabled && !command.Pagination.IsIterator() &&
+		command.Pagination.NextTokenParam != "" && command.Pagination.NextTokenJsonPath != ""
+}
+
+// FetchPages follows the next token starting from startToken until there are no more pages,
+// maxPages pages were fetched, maxItems items were merged or ctx is cancelled.
+// A page failing for another reason fails the fetch with the output of the CLI.
+// onProgress, when set, is called after every page.
+func (command *Command) FetchPages(ctx context.Context, resource string, profile string, startToken string, maxPages int, maxItems int, onProgress func(FetchProgress)) (FetchPagesResult, error) {
+	if !command.SupportsFetchAll() {
+		return FetchPagesResult{}, fmt.Errorf("command %s does not support token based pagination", command.Name)
+	}
+
+	var outputs []string
+	result := FetchPagesResult{}
+	token := startToken
+
+	for {
+		output, err := command.ExecuteWithPaginationToken(ctx, resource, profile, token)
+		if err != nil {
+			if ctx.Err() != nil {
+				result.Cancelled = true
+				break
+			}
+			return FetchPagesResult{}, fmt.Errorf("page %d failed: %w", result.Pages+1, err)
+		}
+
+		items, err := countPageItems(output, command.Parse.AttributeName)
+		if err != nil {
+			if len(outputs) == 0 {
+				return FetchPagesResult{}, err
+			}
+			logger.Logger.Error().Err(err).Int("page", result.Pages+1).Msg("Stopping fetch, page could not be parsed")
+			break
+		}
+
+		outputs = append(outputs, output)
+		result.Pages++
+		result.Items += items
+		token = ExtractPaginationToken(output, *command)
+		result.NextToken = token
+
+		if onProgress != nil {
+			onProgress(FetchProgress{Pages: result.Pages, Items: result.Items})
+		}
+
+		if token == "" || result.Pages >= maxPages || result.Items >= maxItems {
+			break
+		}
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			break
+		}
+	}
+
+	if len(outputs) == 0 {
+		return result, nil
+	}
+
+	merged, err := MergePageOutputs(outputs, command.Parse.AttributeName, command.Pagination.NextTokenJsonPath)
+	if err != nil {
+		return FetchPagesResult{}, err
+	}
+	result.Output = merged
+
+	return result, nil
+}
+
+// MergePageOutputs merges the list found under attributeName of every page into the first page.
+// Known counters are summed and the next token attribute is removed from the result.
+func MergePageOutputs(outputs []string, attributeName string, nextTokenJsonPath string) (string, error) {
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("no pages to merge")
+	}
+
+	merged := orderedmap.New()
+	if err := json.Unmarshal([]byte(outputs[0]), merged); err != nil {
+		return "", fmt.Errorf("unable to parse page 1: %w", err)
+	}
+
+	var items []interface{}
+	counters := map[string]float64{}
+
+	for index, output := range outputs {
+		page := orderedmap.New()
+		if err := json.Unmarshal([]byte(output), page); err != nil {
+			return "", fmt.Errorf("unable to parse page %d: %w", index+1, err)
+		}
+
+		if value, exists := page.Get(attributeName); exists && value != nil {
+			pageItems, ok := value.([]interface{})
+			if !ok {
+				return "", fmt.Errorf("attribute %s of page %d is not a list", attributeName, index+1)
+			}
+			items = append(items, pageItems...)
+		}
+
+		for _, field := range summableCountFields {
+			if value, exists := page.Get(field); exists {
+				if number, ok := value.(float64); ok {
+					counters[field] += number
+				}
+			}
+		}
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+	merged.Set(attributeName, items)
+	for field, total := range counters {
+		merged.Set(field, total)
+	}
+	if nextTokenJsonPath != "" {
+		merged.Delete(nextTokenJsonPath)
+	}
+
+	bytes, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// countPageItems returns the number of entries of the list found under attributeName
+func countPageItems(output string, attributeName string) (int, error) {
+	var page map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &page); err != nil {
+		return 0, fmt.Errorf("unable to parse page: %w", err)
+	}
+	if items, ok := page[attributeName].([]interface{}); ok {
+		return len(items), nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergePageOutputs(t *testing.T) {
+	pages := []string{
+		`{"Items": [{"id": {"S": "foo1"}}, {"id": {"S": "foo2"}}], "Count": 2, "ScannedCount": 2, "LastEvaluatedKey": {"id": {"S": "foo2"}}}`,
+		`{"Items": [{"id": {"S": "foo3"}}], "Count": 1, "ScannedCount": 3}`,
+	}
+
+	merged, err := MergePageOutputs(pages, "Items", "LastEvaluatedKey")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(merged), &result); err != nil {
+		t.Fatalf("Merged output is not valid JSON: %v", err)
+	}
+
+	items, ok := result["Items"].([]interface{})
+	if !ok || len(items) != 3 {
+		t.Fatalf("Expected 3 merged items, got %v", result["Items"])
+	}
+	if result["Count"] != float64(3) || result["ScannedCount"] != float64(5) {
+		t.Errorf("Expected summed counters, got Count=%v ScannedCount=%v", result["Count"], result["ScannedCount"])
+	}
+	if _, exists := result["LastEvaluatedKey"]; exists {
+		t.Errorf("Expected next token to be removed from merged output")
+	}
+}
+
+func TestMergePageOutputsWithEmptyPage(t *testing.T) {
+	pages := []string{
+		`{"QueueUrls": ["https://queue/1"], "NextToken": "abc"}`,
+		`{}`,
+	}
+
+	merged, err := MergePageOutputs(pages, "QueueUrls", "NextToken")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"QueueUrls":["https://queue/1"]}`
+	if merged != expected {
+		t.Errorf("Got %s, expected %s", merged, expected)
+	}
+}
+
+func TestMergePageOutputsRejectsInvalidPage(t *testing.T) {
+	if _, err := MergePageOutputs([]string{`{"Items": []}`, `not json`}, "Items", ""); err == nil {
+		t.Errorf("Expected an error for an invalid page")
+	}
+}
+
+func TestFetchPagesFailsWithCliOutput(t *testing.T) {
+	command := Command{
+		Name:       "scan",
+		Binary:     "sh",
+		Arguments:  []string{"-c", "echo 'An error occurred (AccessDeniedException)' >&2; exit 255"},
+		Parse:      Parse{AttributeName: "Items"},
+		Pagination: &Pagination{Enabled: true, NextTokenParam: "--starting-token", NextTokenJsonPath: "NextToken"},
+	}
+
+	_, err := command.FetchPages(context.Background(), "dynamodb", "default", "", 5, 100, nil)
+	if err == nil || !strings.Contains(err.Error(), "page 1 failed: An error occurred (AccessDeniedException)") {
+		t.Errorf("Expected the CLI error, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "github.com/rivo/tview"
+import (
+	"context"
+
+	"github.com/rivo/tview"
+)
 
 type BreadcrumbType string
 
@@ -14,16 +18,22 @@ const (
 	BreadcrumbDependentCmd  BreadcrumbType = "dependent_command"
 	BreadcrumbJsonView      BreadcrumbType = "json_view"
 	BreadcrumbProcessedJson BreadcrumbType = "processed_json"
+	BreadcrumbInfoView      BreadcrumbType = "info_view" // Read-only view opened by a shortcut, e.g. the DynamoDB table overview
 )
 
 type NavigationState struct {
-	Type              BreadcrumbType
-	Value             string
-	CachedResult      string          // Cached command result for this navigation level
-	CachedBody        tview.Primitive // Cached UI body for this navigation level
-	ProcessedData     interface{}     // Processed JSON data at this level (for nested JSON)
-	PaginationToken   string          // Next page token for paginated commands
-	PaginationHistory []string        // Stack of previous page tokens for backward navigation
+	Type                   BreadcrumbType
+	Value                  string
+	CachedResult           string          // Cached command result for this navigation level
+	CachedBody             tview.Primitive // Cached UI body for this navigation level
+	ProcessedData          interface{}     // Processed JSON data at this level (for nested JSON)
+	PaginationToken        string          // Next page token for paginated commands
+	PaginationHistory      []string        // Stack of previous page tokens for backward navigation
+	RowData                []interface{}   // Raw JSON of each result row shown at this level
+	ServiceState           interface{}     // State of a service specific view at this level, e.g. a DynamoDB query, owned by the view
+	RenderedAsDynamoDBJson bool            // ShowDynamoDBJsonFormat value the cached body was rendered with
+	NeedsRender            bool            // The cached body is outdated, e.g. an item of CachedResult was edited
+	Row                    int             // Table row opened in the JSON viewer, 1 for the first result row
 }
 
 type TableData struct {
@@ -31,6 +41,14 @@ type TableData struct {
 	Headers []string
 	Rows    [][]string
 	RowData []interface{} // For JSON viewer
+
+	VisibleRows []int // Row of the original table shown at each table row while filtered, the header stays at row 0
+}
+
+// MarkedRow is a result row kept to be compared with another row, possibly of another command or profile
+type MarkedRow struct {
+	Label string      // Where the row comes from, e.g. "dev > dynamodb > scan Row 3"
+	Data  interface{} // Raw JSON of the row
 }
 
 type UIState struct {
@@ -43,14 +61,28 @@ type UIState struct {
 	NavigationStack        []NavigationState // Enhanced navigation tracking
 	CommandCache           map[string]string // Cache of command results: "resource:command:params" -> output
 	ViewStack              []tview.Primitive
-	ProcessedJsonData      interface{} // Stores processed JSON data (parsed or decompressed)
-	JsonViewerCallback     func()      // Callback to rebuild JSON viewer
-	SelectedNodeText       string      // Stores the text of the selected node for focus restoration
-	CurrentPageToken       string      // Current page token for active paginated command
-	PageHistory            []string    // Stack of page tokens for backward navigation
-	OriginalTableData      *TableData  // Original unfiltered table data for search/filter
-	ShowDynamoDBJsonFormat bool        // Toggle for DynamoDB JSON format vs regular JSON (true = DynamoDB style)
-	InDynamoDBJsonViewer   bool        // True when viewing a DynamoDB item in the JSON viewer
+	ProcessedJsonData      interface{}        // Stores processed JSON data (parsed or decompressed)
+	JsonViewerCallback     func()             // Callback to rebuild JSON viewer
+	SelectedNodeText       string             // Stores the text of the selected node for focus restoration
+	CurrentPageToken       string             // Current page token for active paginated command
+	PageHistory            []string           // Stack of page tokens for backward navigation
+	OriginalTableData      *TableData         // Original unfiltered table data for search/filter
+	ShowDynamoDBJsonFormat bool               // Toggle for DynamoDB JSON format vs regular JSON (true = DynamoDB style)
+	InDynamoDBJsonViewer   bool               // True when viewing a DynamoDB item in the JSON viewer
+	CancelBackgroundTask   context.CancelFunc // Cancels the running background task (e.g. fetch-all pages), nil when idle
+	MarkedRow              *MarkedRow         // Row marked for compare, kept across navigation
 }
 
 var UiState UIState = UIState{SelectedItems: make(map[string]string), Breadcrumbs: []string{}, NavigationStack: []NavigationState{}, CommandCache: make(map[string]string)}
+
+// OriginalRow returns the row of the original table shown at the given table row, so a filtered table
+// selects the same result row as the unfiltered one. 0 is returned for rows emptied by the filter.
+func (state *UIState) OriginalRow(row int) int {
+	if state.OriginalTableData == nil || state.OriginalTableData.VisibleRows == nil {
+		return row
+	}
+	if row < 0 || row >= len(state.OriginalTableData.VisibleRows) {
+		return 0
+	}
+	return state.OriginalTableData.VisibleRows[row]
+}
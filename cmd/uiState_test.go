@@ -0,0 +1,18 @@
+package cmd
+
+import "testing"
+
+func TestOriginalRow(t *testing.T) {
+	state := UIState{}
+	if row := state.OriginalRow(3); row != 3 {
+		t.Errorf("Expected the row itself without filter, got %d", row)
+	}
+
+	// Rows 2 and 5 of the original table matched the filter
+	state.OriginalTableData = &TableData{VisibleRows: []int{0, 2, 5}}
+	for row, expected := range map[int]int{0: 0, 1: 2, 2: 5, 3: 0} {
+		if original := state.OriginalRow(row); original != expected {
+			t.Errorf("Row %d: expected %d, got %d", row, expected, original)
+		}
+	}
+}
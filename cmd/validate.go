@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Validate checks the loaded resources for broken references and returns every problem found
+func Validate() []error {
+	errs := append([]error{}, LoadErrors...)
+
+	for _, resourceName := range sortedResourceNames() {
+		resource := Resources[resourceName]
+
+		commandNames := make(map[string]bool)
+		for _, command := range resource.Commands {
+			if commandNames[command.Name] {
+				errs = append(errs, fmt.Errorf("%s/%s: duplicated command name", resource.Name, command.Name))
+			}
+			commandNames[command.Name] = true
+		}
+
+		if resource.DefaultCommand != "" && !commandNames[resource.DefaultCommand] {
+			errs = append(errs, fmt.Errorf("%s: default command %q does not exist", resource.Name, resource.DefaultCommand))
+		}
+
+		for _, command := range resource.Commands {
+			if command.DependsOn != "" && !commandNames[command.DependsOn] {
+				errs = append(errs, fmt.Errorf("%s/%s: depends_on command %q does not exist", resource.Name, command.Name, command.DependsOn))
+			}
+			if command.IsPlugin() {
+				if _, err := exec.LookPath(command.Binary); err != nil {
+					errs = append(errs, fmt.Errorf("%s/%s: binary %q not found: %v", resource.Name, command.Name, command.Binary, err))
+				}
+			}
+			if command.Pagination != nil && command.Pagination.Enabled &&
+				command.Pagination.NextTokenParam != "" && command.Pagination.NextTokenJsonPath == "" {
+				errs = append(errs, fmt.Errorf("%s/%s: pagination.nextTokenParam is set without pagination.nextTokenJsonPath", resource.Name, command.Name))
+			}
+			if command.Pagination != nil && command.Pagination.Type != "" &&
+				command.Pagination.Type != PaginationToken && command.Pagination.Type != PaginationIterator {
+				errs = append(errs, fmt.Errorf("%s/%s: unknown pagination.type %q", resource.Name, command.Name, command.Pagination.Type))
+			}
+			if command.Pagination.IsIterator() && (command.Pagination.NextTokenParam == "" || command.Pagination.NextTokenJsonPath == "") {
+				errs = append(errs, fmt.Errorf("%s/%s: iterator pagination requires pagination.nextTokenParam and pagination.nextTokenJsonPath", resource.Name, command.Name))
+			}
+		}
+	}
+
+	return errs
+}
+
+// DescribeResources writes every loaded command together with its expanded command line
+func DescribeResources(w io.Writer) {
+	for _, resourceName := range sortedResourceNames() {
+		resource := Resources[resourceName]
+		fmt.Fprintf(w, "%s (%d commands)\n", resource.Name, len(resource.Commands))
+
+		for _, command := range resource.Commands {
+			title := command.Name
+			if command.DependsOn != "" {
+				title = fmt.Sprintf("%s (depends on %s)", command.Name, command.DependsOn)
+			}
+			binaryName, args := command.CommandLine(resource.Name, "<profile>", "")
+			fmt.Fprintf(w, "  %s\n    %s %s\n", title, binaryName, strings.Join(args, " "))
+		}
+	}
+}
+
+func sortedResourceNames() []string {
+	names := GetAvailableResourceNames()
+	sort.Strings(names)
+	return names
+}
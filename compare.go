@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// handleMarkForCompare keeps the selected row, it stays marked while navigating to another command, table or profile
+func handleMarkForCompare(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	row, rowData, ok := getSelectedRowData()
+	if !ok {
+		return event
+	}
+
+	cmd.UiState.MarkedRow = &cmd.MarkedRow{Label: compareRowLabel(row), Data: rowData}
+	logger.Logger.Info().Str("row", cmd.UiState.MarkedRow.Label).Msg("Row marked for compare")
+	return nil
+}
+
+// handleCompareWithMarked shows the diff between the marked row and the selected row
+func handleCompareWithMarked(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	row, rowData, ok := getSelectedRowData()
+	if !ok {
+		return event
+	}
+	if cmd.UiState.MarkedRow == nil {
+		logger.Logger.Warn().Msg("No row marked for compare, mark one with 'm' first")
+		return nil
+	}
+
+	marked := cmd.UiState.MarkedRow
+	pushNavigation(cmd.BreadcrumbInfoView, fmt.Sprintf("Diff Row %d", row))
+
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+	Body = ui.CreateJsonDiffViewer(ui.JsonDiffViewerProperties{
+		Title:          "Diff",
+		LeftTitle:      marked.Label,
+		RightTitle:     compareRowLabel(row),
+		Left:           marked.Data,
+		Right:          rowData,
+		DynamoDBFormat: cmd.UiState.ShowDynamoDBJsonFormat,
+	})
+	updateRootView(nil)
+	App.SetFocus(Body)
+	return nil
+}
+
+// isJsonDiffView reports whether the current view is the diff of two rows
+func isJsonDiffView() bool {
+	currentNav := peekNavigation()
+	if currentNav == nil || currentNav.Type != cmd.BreadcrumbInfoView {
+		return false
+	}
+	_, ok := Body.(*tview.TreeView)
+	return ok
+}
+
+// compareRowLabel describes where a row comes from with the breadcrumbs after the profiles list, e.g. "dev > dynamodb > scan Row 3"
+func compareRowLabel(row int) string {
+	breadcrumbs := cmd.UiState.Breadcrumbs
+	if len(breadcrumbs) > 1 {
+		breadcrumbs = breadcrumbs[1:]
+	}
+	return fmt.Sprintf("%s Row %d", strings.Join(breadcrumbs, " > "), row)
+}
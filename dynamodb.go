@@ -5,17 +5,25 @@ import (
 	"strings"
 
 	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
 	"github.com/cmd-tools/aws-commander/logger"
 	"github.com/cmd-tools/aws-commander/ui"
 	"github.com/rivo/tview"
 )
 
-// KeyInfo represents a DynamoDB key with its metadata
-type KeyInfo struct {
-	Name     string // Key name
-	Type     string // PK (Partition Key) or SK (Sort Key)
-	AttrType string // S (String), N (Number), B (Binary)
-}
+// Form fields holding the sort key condition, next to one field per key name
+const (
+	sortKeyOperatorField   = "__sortKeyOperator"
+	sortKeyUpperBoundField = "__sortKeyUpperBound"
+)
+
+// Sort key operators supported by --key-condition-expression
+const (
+	operatorBetween    = "BETWEEN"
+	operatorBeginsWith = "begins_with"
+)
+
+var sortKeyOperators = []string{"=", "<", "<=", ">", ">=", operatorBetween, operatorBeginsWith}
 
 // showKeyInputForm displays an input form for DynamoDB query parameters
 func showKeyInputForm() {
@@ -25,15 +33,27 @@ func showKeyInputForm() {
 	cmd.UiState.OriginalTableData = nil
 
 	selectedIndexName := getSelectedIndexName()
-	indexKeys, indexType := extractIndexDetails(selectedIndexName)
+	index, ok := findTableIndex(selectedIndexName)
+
+	// A saved query fills the form of the index it queries
+	if !ok {
+		if indexName, loaded := loadSavedQueryState(selectedIndexName); loaded {
+			index, ok = findTableIndex(indexName)
+		}
+	}
+	if !ok {
+		logger.Logger.Warn().Str("index", selectedIndexName).Msg("Unknown index, unable to build the query form")
+		createQueryCancelHandler()()
+		return
+	}
 
 	logger.Logger.Debug().
-		Str("selectedIndexName", selectedIndexName).
-		Str("indexType", indexType).
-		Interface("indexKeys", indexKeys).
-		Msg("Extracted index details for query")
+		Str("index", index.Name).
+		Str("indexType", index.Type).
+		Interface("indexKeys", index.Keys).
+		Msg("Index details for query")
 
-	inputForm := createQueryInputForm(selectedIndexName, indexKeys, indexType)
+	inputForm := createQueryInputForm(index)
 
 	Body = inputForm
 	updateRootView(nil)
@@ -63,167 +83,328 @@ func getSelectedIndexName() string {
 	return ""
 }
 
-// extractIndexDetails parses index information from the cached describe-table result
-func extractIndexDetails(selectedIndexName string) ([]KeyInfo, string) {
-	var indexKeys []KeyInfo
-	var indexType string
-
-	// Find the describe-table navigation state with cached result
+// findTableIndex returns the named index of the selected table, from the index list or else from a description of the table
+func findTableIndex(name string) (dynamodb.Index, bool) {
 	for i := len(cmd.UiState.NavigationStack) - 1; i >= 0; i-- {
-		state := cmd.UiState.NavigationStack[i]
-		if state.Type == cmd.BreadcrumbDependentCmd && state.Value == "describe-table" && state.CachedBody != nil {
-			if table, ok := state.CachedBody.(*tview.Table); ok {
-				indexKeys, indexType = parseIndexFromTable(table, selectedIndexName)
-				break
-			}
+		if indexes := dynamoDBState(&cmd.UiState.NavigationStack[i]).Indexes; indexes != nil {
+			return dynamodb.FindIndex(indexes, name)
 		}
 	}
 
-	return indexKeys, indexType
+	table, err := describeCurrentTable()
+	if err != nil {
+		logger.Logger.Debug().Err(err).Msg("Unable to describe table, its indexes are unknown")
+		return dynamodb.Index{}, false
+	}
+	return dynamodb.FindIndex(table.Indexes(), name)
 }
 
-// parseIndexFromTable extracts key details from the describe-table result table
-func parseIndexFromTable(table *tview.Table, selectedIndexName string) ([]KeyInfo, string) {
-	var indexKeys []KeyInfo
-	var indexType string
+// createQueryInputForm creates the input form for query parameters
+func createQueryInputForm(index dynamodb.Index) *tview.Form {
+	// Create input fields for all keys in the index, filled with the values of a saved query
+	state := currentQueryState()
+	var inputFields []ui.InputField
+
+	partitionKeys := index.PartitionKeys()
+	for position, key := range partitionKeys {
+		inputFields = append(inputFields, ui.InputField{
+			Label:        keyFieldLabel(key, position, len(partitionKeys)),
+			Key:          key.Name,
+			DefaultValue: state.KeyValues[key.Name],
+		})
+	}
 
-	rowCount := table.GetRowCount()
-	for row := 1; row < rowCount; row++ { // Skip header row
-		indexNameCell := table.GetCell(row, 0)
-		if indexNameCell == nil || indexNameCell.Text != selectedIndexName {
-			continue
+	// Sort key attributes are optional, the operator applies to the last one set
+	if sortKeys := index.SortKeys(); len(sortKeys) > 0 {
+		operatorLabel := fmt.Sprintf("%s operator", sortKeys[0].Name)
+		upperBoundLabel := fmt.Sprintf("%s upper bound (BETWEEN only)", sortKeys[0].Name)
+		if len(sortKeys) > 1 {
+			operatorLabel = "Sort key operator (last attribute set)"
+			upperBoundLabel = "Sort key upper bound (BETWEEN only)"
 		}
 
-		// Found the selected index row
-		indexTypeCell := table.GetCell(row, 1)
-		if indexTypeCell != nil {
-			indexType = indexTypeCell.Text
+		operator := state.KeyValues[sortKeyOperatorField]
+		if operator == "" {
+			operator = "="
 		}
-
-		keyDetailsCell := table.GetCell(row, 3)
-		if keyDetailsCell != nil {
-			indexKeys = parseKeyDetails(keyDetailsCell.Text)
+		inputFields = append(inputFields, ui.InputField{
+			Label:        operatorLabel,
+			Key:          sortKeyOperatorField,
+			DefaultValue: operator,
+			Options:      sortKeyOperators,
+		})
+		for position, key := range sortKeys {
+			inputFields = append(inputFields, ui.InputField{
+				Label:        keyFieldLabel(key, position, len(sortKeys)),
+				Key:          key.Name,
+				DefaultValue: state.KeyValues[key.Name],
+			})
 		}
-		break
+		inputFields = append(inputFields, ui.InputField{
+			Label:        upperBoundLabel,
+			Key:          sortKeyUpperBoundField,
+			DefaultValue: state.KeyValues[sortKeyUpperBoundField],
+		})
 	}
 
-	return indexKeys, indexType
-}
+	inputFields = append(inputFields, queryOptionFields(*state, index.IsGlobal())...)
 
-// parseKeyDetails parses key information from formatted string
-// Format: "KeyName (PK:S), KeyName (SK:S)"
-func parseKeyDetails(keyDetailsText string) []KeyInfo {
-	var indexKeys []KeyInfo
-
-	keyDetailsParts := strings.Split(keyDetailsText, ", ")
-	for _, keyDetail := range keyDetailsParts {
-		if idx := strings.Index(keyDetail, " ("); idx > 0 {
-			keyName := keyDetail[:idx]
-			typesPart := keyDetail[idx+2 : len(keyDetail)-1] // Remove " (" and ")"
-			types := strings.Split(typesPart, ":")
-			if len(types) == 2 {
-				indexKeys = append(indexKeys, KeyInfo{
-					Name:     keyName,
-					Type:     types[0], // PK or SK
-					AttrType: types[1], // S, N, B
-				})
-			}
+	// Build title showing index name, with the projection and status of secondary indexes
+	formTitle := fmt.Sprintf(" Enter values for: %s ", index.Name)
+	if len(index.Keys) == 1 {
+		formTitle = fmt.Sprintf(" Enter value for: %s ", index.Name)
+	}
+	if summary := index.Summary(); summary != "" {
+		formTitle = fmt.Sprintf("%s(%s) ", formTitle, summary)
+	}
+
+	// Every secondary index projects the table keys
+	var tableKeys []string
+	if primary, ok := findTableIndex(dynamodb.PrimaryIndexName); ok {
+		for _, key := range primary.Keys {
+			tableKeys = append(tableKeys, key.Name)
 		}
 	}
 
-	return indexKeys
+	return ui.CreateInputForm(ui.InputFormProperties{
+		Title:      formTitle,
+		Notes:      index.Notes(tableKeys),
+		Fields:     inputFields,
+		OnValidate: createQueryValidateHandler(index),
+		OnSubmit:   createQuerySubmitHandler(index),
+		OnCancel:   createQueryCancelHandler(),
+		App:        App,
+	})
 }
 
-// createQueryInputForm creates the input form for query parameters
-func createQueryInputForm(selectedIndexName string, indexKeys []KeyInfo, indexType string) *tview.Form {
-	// Create input fields for all keys in the index
-	var inputFields []ui.InputField
+// keyFieldLabel labels a key field, with its position among the attributes of a composite key, e.g. "region (PK 2/2)"
+func keyFieldLabel(key dynamodb.IndexKey, position int, count int) string {
+	keyType := "SK"
+	if key.IsPartitionKey() {
+		keyType = "PK"
+	}
+	if count > 1 {
+		keyType = fmt.Sprintf("%s %d/%d", keyType, position+1, count)
+	}
+	if !key.IsPartitionKey() {
+		keyType += ", optional"
+	}
+	return fmt.Sprintf("%s (%s)", key.Name, keyType)
+}
+
+// lastSetSortKey returns the last sort key attribute with a value, the one the sort key operator applies to
+func lastSetSortKey(indexKeys []dynamodb.IndexKey, values map[string]string) (dynamodb.IndexKey, bool) {
+	var lastKey dynamodb.IndexKey
+	found := false
 	for _, key := range indexKeys {
-		displayLabel := fmt.Sprintf("%s (%s)", key.Name, key.Type)
-		// Mark sort key as optional for primary key queries
-		if key.Type == "SK" && indexType == "Primary Index" {
-			displayLabel = fmt.Sprintf("%s (%s, optional)", key.Name, key.Type)
+		if !key.IsPartitionKey() && values[key.Name] != "" {
+			lastKey, found = key, true
 		}
-		inputFields = append(inputFields, ui.InputField{
-			Label:        displayLabel,
-			Key:          key.Name,
-			DefaultValue: "",
-		})
 	}
+	return lastKey, found
+}
 
-	// Build title showing index name
-	formTitle := fmt.Sprintf(" Enter values for: %s ", selectedIndexName)
-	if len(indexKeys) == 1 {
-		formTitle = fmt.Sprintf(" Enter value for: %s ", selectedIndexName)
-	}
+// createQueryValidateHandler returns the validation of the query form, its errors are shown inline in the form
+func createQueryValidateHandler(index dynamodb.Index) func(map[string]string) error {
+	return func(values map[string]string) error {
+		// Every partition key attribute is required, only with equality
+		for _, key := range index.PartitionKeys() {
+			if values[key.Name] == "" {
+				return fmt.Errorf("partition key %s cannot be empty", key.Name)
+			}
+		}
 
-	return ui.CreateInputForm(ui.InputFormProperties{
-		Title:    formTitle,
-		Fields:   inputFields,
-		OnSubmit: createQuerySubmitHandler(indexKeys, indexType, selectedIndexName),
-		OnCancel: createQueryCancelHandler(),
-		App:      App,
-	})
+		// Sort key attributes are set from left to right, without gaps
+		sortKeys := index.SortKeys()
+		for position := 1; position < len(sortKeys); position++ {
+			if values[sortKeys[position].Name] != "" && values[sortKeys[position-1].Name] == "" {
+				return fmt.Errorf("sort key %s must be set before %s", sortKeys[position-1].Name, sortKeys[position].Name)
+			}
+		}
+		if key, ok := lastSetSortKey(index.Keys, values); ok {
+			operator := values[sortKeyOperatorField]
+			if operator == operatorBetween && values[sortKeyUpperBoundField] == "" {
+				return fmt.Errorf("BETWEEN requires an upper bound value for %s", key.Name)
+			}
+			if operator == operatorBeginsWith && key.AttributeType == "N" {
+				return fmt.Errorf("begins_with is not supported on number sort key %s", key.Name)
+			}
+		}
+
+		// Values must match the key attribute types (numbers for N, base64 for B)
+		if _, err := buildQueryExpression(index.Keys, values); err != nil {
+			return err
+		}
+		candidate := *currentQueryState()
+		return applyQueryOptionValues(&candidate, values, index.IsGlobal())
+	}
 }
 
 // createQuerySubmitHandler returns the submit handler for the query form
-func createQuerySubmitHandler(indexKeys []KeyInfo, indexType, selectedIndexName string) func(map[string]string) {
+func createQuerySubmitHandler(index dynamodb.Index) func(map[string]string) {
 	return func(values map[string]string) {
-		// Validate that partition key (PK) has a value - it's always required
-		// Sort key (SK) is optional for primary key queries
-		for _, key := range indexKeys {
-			if key.Type == "PK" && values[key.Name] == "" {
-				logger.Logger.Warn().Str("key", key.Name).Msg("Partition key value cannot be empty")
-				return
-			}
-			// For non-primary indexes or when SK is provided, validate it's not empty
-			// But for primary index, SK can be empty
-			if key.Type == "SK" && indexType != "Primary Index" && values[key.Name] == "" {
-				logger.Logger.Warn().Str("key", key.Name).Msg("Sort key value cannot be empty for this index type")
-				return
-			}
+		keyCondition, err := buildQueryExpression(index.Keys, values)
+		if err != nil {
+			logger.Logger.Warn().Err(err).Msg("Invalid query expression")
+			return
 		}
 
-		// Build the key-condition-expression
-		keyConditionExpr, expressionAttrValues, expressionAttrNames := buildQueryExpression(indexKeys, values)
+		// Build the key-condition-expression, keeping the filter of a previous run at this level
+		state := currentQueryState()
+		state.KeyCondition = keyCondition
+		state.KeyValues = map[string]string{}
+		for _, field := range []string{sortKeyOperatorField, sortKeyUpperBoundField} {
+			if value, ok := values[field]; ok {
+				state.KeyValues[field] = value
+			}
+		}
+		for _, key := range index.Keys {
+			state.KeyValues[key.Name] = values[key.Name]
+		}
+		state.IndexName = ""
+		if err := applyQueryOptionValues(state, values, index.IsGlobal()); err != nil {
+			logger.Logger.Warn().Err(err).Msg("Invalid query options")
+			return
+		}
 
 		logger.Logger.Debug().
-			Str("keyConditionExpr", keyConditionExpr).
-			Str("expressionAttrValues", expressionAttrValues).
-			Str("expressionAttrNames", expressionAttrNames).
+			Str("keyConditionExpr", state.KeyCondition.Expression).
+			Interface("expressionAttrValues", state.KeyCondition.Values).
+			Interface("expressionAttrNames", state.KeyCondition.Names).
 			Msg("Built query expression")
 
-		// Add the query parameters to the command arguments
-		cmd.UiState.Command.Arguments = append(cmd.UiState.Command.Arguments,
-			"--key-condition-expression", keyConditionExpr,
-			"--expression-attribute-values", expressionAttrValues,
-		)
-
-		if expressionAttrNames != "" {
-			cmd.UiState.Command.Arguments = append(cmd.UiState.Command.Arguments,
-				"--expression-attribute-names", expressionAttrNames,
-			)
+		// If querying a GSI or LSI, add the --index-name parameter
+		if index.IsSecondary() {
+			state.IndexName = index.Name
 		}
 
-		// If querying a GSI or LSI, add the --index-name parameter
-		if indexType == "Global Secondary Index" || indexType == "Local Secondary Index" {
-			cmd.UiState.Command.Arguments = append(cmd.UiState.Command.Arguments,
-				"--index-name", selectedIndexName,
-			)
+		runQueryState(state)
+	}
+}
+
+// currentQueryState returns the scan or query state of the current navigation level, creating it when missing
+func currentQueryState() *dynamodb.QueryState {
+	currentNav := peekNavigation()
+	if currentNav == nil {
+		return &dynamodb.QueryState{}
+	}
+	state := dynamoDBState(currentNav)
+	if state.Query == nil {
+		state.Query = &dynamodb.QueryState{}
+	}
+	return state.Query
+}
+
+// dynamoDBNavigationState is the DynamoDB state of a navigation level, kept in its ServiceState
+type dynamoDBNavigationState struct {
+	Query        *dynamodb.QueryState     // Key condition and filter of the scan or query run at this level
+	Statement    *dynamodb.Statement      // PartiQL statement run at this level
+	Stream       *dynamodb.StreamPosition // Shard and iterator of the stream records read at this level
+	Indexes      []dynamodb.Index         // Indexes of the table listed at this level (describe-table)
+	TableCompare *dynamodb.CompareReport  // Differences of the two tables compared at this level
+}
+
+// dynamoDBState returns the DynamoDB state of the navigation level, creating it when missing
+func dynamoDBState(nav *cmd.NavigationState) *dynamoDBNavigationState {
+	state, ok := nav.ServiceState.(*dynamoDBNavigationState)
+	if !ok {
+		state = &dynamoDBNavigationState{}
+		nav.ServiceState = state
+	}
+	return state
+}
+
+// tableNamePlaceHolder is the selected table name, set by list-tables
+const tableNamePlaceHolder = cmd.VariablePlaceHolderPrefix + "TABLENAME"
+
+// describeCurrentTable returns the description of the selected table, cached per profile and table
+func describeCurrentTable() (dynamodb.TableDescription, error) {
+	return describeTable(cmd.UiState.SelectedItems[tableNamePlaceHolder])
+}
+
+// describeTableErrors holds the failed describes by cache key, so a missing permission does not run describe-table on every render
+var describeTableErrors = map[string]error{}
+
+// describeTable returns the description of the table, cached per profile and table like its failure
+func describeTable(tableName string) (dynamodb.TableDescription, error) {
+	if tableName == "" {
+		return dynamodb.TableDescription{}, fmt.Errorf("no table selected")
+	}
+
+	cacheKey := describeTableCacheKey(tableName)
+	if err, failed := describeTableErrors[cacheKey]; failed {
+		return dynamodb.TableDescription{}, err
+	}
+	output, cached := cmd.UiState.CommandCache[cacheKey]
+	if !cached {
+		var err error
+		output, err = runTableCommand("describe-table", tableName)
+		if err != nil {
+			describeTableErrors[cacheKey] = err
+			return dynamodb.TableDescription{}, err
 		}
+		cmd.UiState.CommandCache[cacheKey] = output
+	}
 
-		// Reset pagination state for new query
-		cmd.UiState.CurrentPageToken = ""
-		cmd.UiState.PageHistory = []string{}
+	return dynamodb.ParseTableDescription(output)
+}
 
-		// Execute the query command
-		_, body := executeCommand(cmd.UiState.Command)
-		Body = body
+// forgetTableDescription drops the cached description or failure of the table, the next describe runs the command again
+func forgetTableDescription(tableName string) {
+	cacheKey := describeTableCacheKey(tableName)
+	delete(cmd.UiState.CommandCache, cacheKey)
+	delete(describeTableErrors, cacheKey)
+}
 
-		updateRootView(nil)
+func describeTableCacheKey(tableName string) string {
+	return fmt.Sprintf("%s:describe-table:%s:%s", cmd.UiState.Resource.Name, cmd.UiState.Profile, tableName)
+}
+
+// isDynamoDBItems reports whether the command lists DynamoDB items (scan, query)
+func isDynamoDBItems(command cmd.Command) bool {
+	return cmd.UiState.Resource.Name == "dynamodb" && command.Parse.Type == "object" && command.Parse.AttributeName == "Items"
+}
+
+// applyDynamoDBColumns shows the key attributes first and restricts the columns to the ones chosen for the current level
+func applyDynamoDBColumns(command *cmd.Command) {
+	if !isDynamoDBItems(*command) {
+		return
+	}
+
+	if table, err := describeCurrentTable(); err == nil {
+		command.Parse.KeyColumns = table.KeyAttributes()
+	} else {
+		logger.Logger.Debug().Err(err).Msg("Unable to describe table, key attributes are not shown first")
+	}
+
+	if currentNav := peekNavigation(); currentNav != nil && dynamoDBState(currentNav).Query != nil {
+		command.Parse.Columns = dynamoDBState(currentNav).Query.Columns
 	}
 }
 
+// runQueryState runs the current scan or query command with the arguments of the state
+func runQueryState(state *dynamodb.QueryState) bool {
+	arguments, err := state.Arguments()
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Invalid query expression")
+		return false
+	}
+
+	// Start from the configured arguments, so running again replaces the previous expressions and a page size replaces the configured limit
+	command := cmd.UiState.Resource.GetCommand(cmd.UiState.Command.Name)
+	cmd.UiState.Command = command.WithArguments(arguments...)
+
+	// Reset pagination state for new query
+	cmd.UiState.CurrentPageToken = ""
+	cmd.UiState.PageHistory = []string{}
+
+	_, body := executeCommand(cmd.UiState.Command)
+	Body = body
+
+	updateRootView(nil)
+	return true
+}
+
 var dynamoReservedWords = map[string]bool{
 	"ABORT": true, "ABSOLUTE": true, "ACTION": true, "ADD": true, "AFTER": true, "AGENT": true, "AGGREGATE": true, "ALL": true, "ALLOCATE": true, "ALTER": true,
 	"ANALYZE": true, "AND": true, "ANY": true, "ARCHIVE": true, "ARE": true, "ARRAY": true, "AS": true, "ASC": true, "ASCII": true, "ASENSITIVE": true,
@@ -291,12 +472,14 @@ func isReservedWord(word string) bool {
 	return dynamoReservedWords[strings.ToUpper(word)]
 }
 
-// buildQueryExpression builds the DynamoDB query expression and attribute values
-func buildQueryExpression(indexKeys []KeyInfo, values map[string]string) (string, string, string) {
+// buildQueryExpression builds the DynamoDB key condition expression and its placeholders.
+// Keys are compared with equality, except the last sort key attribute set which uses the selected operator.
+// Values are checked against the key attribute type, so the command never runs with malformed input.
+func buildQueryExpression(indexKeys []dynamodb.IndexKey, values map[string]string) (dynamodb.Expression, error) {
 	var keyConditionParts []string
-	expressionAttrValuesMap := make(map[string]map[string]string)
-	expressionAttrNamesMap := make(map[string]string)
+	expression := dynamodb.Expression{Names: map[string]string{}, Values: map[string]dynamodb.AttributeValue{}}
 	placeholderIndex := 0
+	lastSortKey, _ := lastSetSortKey(indexKeys, values)
 
 	for _, key := range indexKeys {
 		// Skip keys with empty values (e.g., optional sort key)
@@ -310,40 +493,42 @@ func buildQueryExpression(indexKeys []KeyInfo, values map[string]string) (string
 		// Handle reserved words by using expression attribute names
 		keyRef := key.Name
 		if isReservedWord(key.Name) {
-			keyRef = fmt.Sprintf("#key%d", len(expressionAttrNamesMap))
-			expressionAttrNamesMap[keyRef] = key.Name
+			keyRef = fmt.Sprintf("#key%d", len(expression.Names))
+			expression.Names[keyRef] = key.Name
 		}
 
-		// Add condition for key
-		keyConditionParts = append(keyConditionParts, fmt.Sprintf("%s = %s", keyRef, placeholder))
-
 		// Map attribute type (S, N, B) to value
-		expressionAttrValuesMap[placeholder] = map[string]string{
-			key.AttrType: values[key.Name],
+		value, err := dynamodb.NewKeyValue(key.AttributeType, values[key.Name])
+		if err != nil {
+			return dynamodb.Expression{}, fmt.Errorf("%s: %w", key.Name, err)
 		}
-	}
+		expression.Values[placeholder] = value
 
-	keyConditionExpr := strings.Join(keyConditionParts, " AND ")
-
-	// Build expression-attribute-values JSON
-	var attrValuePairs []string
-	for placeholder, attrValue := range expressionAttrValuesMap {
-		for attrType, value := range attrValue {
-			attrValuePairs = append(attrValuePairs, fmt.Sprintf(`"%s": {"%s": "%s"}`, placeholder, attrType, value))
+		// Partition keys only support equality, the last sort key attribute uses the selected operator
+		operator := "="
+		if key == lastSortKey && values[sortKeyOperatorField] != "" {
+			operator = values[sortKeyOperatorField]
 		}
-	}
-	expressionAttrValues := fmt.Sprintf(`{%s}`, strings.Join(attrValuePairs, ", "))
 
-	var expressionAttrNames string
-	if len(expressionAttrNamesMap) > 0 {
-		var attrNamePairs []string
-		for placeholder, keyName := range expressionAttrNamesMap {
-			attrNamePairs = append(attrNamePairs, fmt.Sprintf(`"%s": "%s"`, placeholder, keyName))
+		switch operator {
+		case operatorBetween:
+			upperPlaceholder := fmt.Sprintf(":val%d", placeholderIndex)
+			placeholderIndex++
+			upperValue, err := dynamodb.NewKeyValue(key.AttributeType, values[sortKeyUpperBoundField])
+			if err != nil {
+				return dynamodb.Expression{}, fmt.Errorf("%s upper bound: %w", key.Name, err)
+			}
+			expression.Values[upperPlaceholder] = upperValue
+			keyConditionParts = append(keyConditionParts, fmt.Sprintf("%s BETWEEN %s AND %s", keyRef, placeholder, upperPlaceholder))
+		case operatorBeginsWith:
+			keyConditionParts = append(keyConditionParts, fmt.Sprintf("begins_with(%s, %s)", keyRef, placeholder))
+		default:
+			keyConditionParts = append(keyConditionParts, fmt.Sprintf("%s %s %s", keyRef, operator, placeholder))
 		}
-		expressionAttrNames = fmt.Sprintf(`{%s}`, strings.Join(attrNamePairs, ", "))
 	}
 
-	return keyConditionExpr, expressionAttrValues, expressionAttrNames
+	expression.Expression = strings.Join(keyConditionParts, " AND ")
+	return expression, nil
 }
 
 // createQueryCancelHandler returns the cancel handler for the query form
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	commandParser "github.com/cmd-tools/aws-commander/parser"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the backup and restore forms
+const (
+	backupNameField       = "__backupName"
+	targetTableField      = "__targetTable"
+	latestRestorableField = "__latestRestorable"
+	restoreDateTimeField  = "__restoreDateTime"
+)
+
+// isBackupList reports whether the command lists the on-demand backups of a DynamoDB table (list-backups)
+func isBackupList(command cmd.Command) bool {
+	return cmd.UiState.Resource.Name == "dynamodb" && command.Parse.AttributeName == "BackupSummaries"
+}
+
+// isBackupListCommand reports whether the current view lists the backups of a DynamoDB table
+func isBackupListCommand() bool {
+	currentNav := peekNavigation()
+	return isBackupList(cmd.UiState.Command) && currentNav != nil && currentNav.Value == cmd.UiState.Command.Name &&
+		(currentNav.Type == cmd.BreadcrumbCommand || currentNav.Type == cmd.BreadcrumbDependentCmd)
+}
+
+// backupSummary shows the point-in-time recovery status and period of the table next to its backups
+func backupSummary(commandParsed *commandParser.ParseCommandResult) {
+	backups, err := describeContinuousBackups(cmd.UiState.SelectedItems[tableNamePlaceHolder])
+	if err != nil {
+		logger.Logger.Debug().Err(err).Msg("Unable to describe the continuous backups")
+		commandParsed.Summary = "PITR unknown"
+		return
+	}
+	commandParsed.Summary = backups.RecoverySummary()
+}
+
+func describeContinuousBackups(tableName string) (dynamodb.ContinuousBackups, error) {
+	output, err := runTableCommand("describe-continuous-backups", tableName)
+	if err != nil {
+		return dynamodb.ContinuousBackups{}, err
+	}
+	return dynamodb.ParseContinuousBackups(output)
+}
+
+// canManageBackups reports whether the current profile may create backups and restore tables
+func canManageBackups() bool {
+	if cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+		logger.Logger.Warn().Str("profile", cmd.UiState.Profile).Msg("The profile is read-only, backups can not be created or restored")
+		return false
+	}
+	return true
+}
+
+// handleCreateBackup asks the name of an on-demand backup of the selected table
+func handleCreateBackup(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	tableName, ok := cmd.UiState.SelectedItems[tableNamePlaceHolder], isBackupListCommand()
+	if !ok {
+		tableName, ok = selectedTableName()
+	}
+	if !ok || !canManageBackups() {
+		return event
+	}
+
+	showCreateBackupForm(tableName, Body, dynamodb.DefaultBackupName(tableName, time.Now()), "")
+	return nil
+}
+
+// showCreateBackupForm displays the backup form with the given name, and the error of a previous attempt if any
+func showCreateBackupForm(tableName string, previousBody tview.Primitive, backupName string, formError string) {
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" On-demand backup of %s ", tableName),
+		Fields: []ui.InputField{{Label: "Backup name", Key: backupNameField, DefaultValue: backupName}},
+		OnValidate: func(values map[string]string) error {
+			_, err := dynamodb.CreateBackupArguments(tableName, values[backupNameField])
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			arguments, _ := dynamodb.CreateBackupArguments(tableName, values[backupNameField])
+			confirmBackupAction(
+				fmt.Sprintf("Create the on-demand backup %s of %s? Backups are billed by size until they are deleted.", strings.TrimSpace(values[backupNameField]), tableName),
+				cmd.UiState.Resource.NewCommand("create-backup", arguments...),
+				previousBody,
+				func(output string) (string, error) {
+					name, status, err := dynamodb.ParseCreatedBackup(output)
+					return fmt.Sprintf("Backup %s of %s is %s.", name, tableName, status), err
+				},
+				func(commandError string) {
+					showCreateBackupForm(tableName, previousBody, values[backupNameField], commandError)
+				},
+			)
+		},
+		OnCancel: func() { restoreBackupView(previousBody) },
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// handleRestoreBackup asks the name of the new table the selected backup is restored into
+func handleRestoreBackup(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isBackupListCommand() || !canManageBackups() {
+		return event
+	}
+
+	_, rowData, ok := getSelectedRowData()
+	if !ok {
+		return event
+	}
+	rowJson, err := json.Marshal(rowData)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal selected row")
+		return nil
+	}
+	backup, err := dynamodb.ParseBackupSummary(rowJson)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("The selected row is not a backup")
+		return nil
+	}
+
+	showRestoreBackupForm(backup, Body, backup.TableName+"-restored", "")
+	return nil
+}
+
+// showRestoreBackupForm displays the restore form of a backup, and the error of a previous attempt if any
+func showRestoreBackupForm(backup dynamodb.BackupSummary, previousBody tview.Primitive, targetTableName string, formError string) {
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Restore the backup %s (%s) ", backup.BackupName, backup.BackupCreationDateTime),
+		Fields: []ui.InputField{{Label: "New table name", Key: targetTableField, DefaultValue: targetTableName}},
+		OnValidate: func(values map[string]string) error {
+			_, err := dynamodb.RestoreBackupArguments(backup, values[targetTableField])
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			arguments, _ := dynamodb.RestoreBackupArguments(backup, values[targetTableField])
+			confirmBackupAction(
+				fmt.Sprintf("Restore the backup %s of %s into the new table %s? The restore can take a while and the new table is billed like any table.", backup.BackupName, backup.TableName, strings.TrimSpace(values[targetTableField])),
+				cmd.UiState.Resource.NewCommand("restore-table-from-backup", arguments...),
+				previousBody,
+				restoredTableMessage,
+				func(commandError string) {
+					showRestoreBackupForm(backup, previousBody, values[targetTableField], commandError)
+				},
+			)
+		},
+		OnCancel: func() { restoreBackupView(previousBody) },
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// handleRestorePointInTime asks the new table name and the time the table of the backups is restored to
+func handleRestorePointInTime(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isBackupListCommand() || !canManageBackups() {
+		return event
+	}
+
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	backups, err := describeContinuousBackups(tableName)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("table", tableName).Msg("Unable to describe the continuous backups")
+		return nil
+	}
+	if !backups.IsPointInTimeRecoveryEnabled() {
+		logger.Logger.Warn().Str("table", tableName).Msg("Point-in-time recovery is not enabled, the table can not be restored to a point in time")
+		return nil
+	}
+
+	restore := dynamodb.PointInTimeRestore{SourceTableName: tableName, TargetTableName: tableName + "-restored"}
+	showPointInTimeRestoreForm(backups, Body, restore, "")
+	return nil
+}
+
+// showPointInTimeRestoreForm displays the point-in-time restore form, and the error of a previous attempt if any
+func showPointInTimeRestoreForm(backups dynamodb.ContinuousBackups, previousBody tview.Primitive, restore dynamodb.PointInTimeRestore, formError string) {
+	recovery := backups.PointInTimeRecoveryDescription
+	restoreDateTime := restore.RestoreDateTime
+	if restoreDateTime == "" {
+		restoreDateTime = string(recovery.LatestRestorableDateTime)
+	}
+
+	readRestore := func(values map[string]string) dynamodb.PointInTimeRestore {
+		candidate := dynamodb.PointInTimeRestore{SourceTableName: restore.SourceTableName, TargetTableName: values[targetTableField]}
+		if values[latestRestorableField] != "true" {
+			candidate.RestoreDateTime = values[restoreDateTimeField]
+		}
+		return candidate
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Restore %s to a point in time ", restore.SourceTableName),
+		Notes: []string{fmt.Sprintf("Restorable from %s to %s", recovery.EarliestRestorableDateTime, recovery.LatestRestorableDateTime)},
+		Fields: []ui.InputField{
+			{Label: "New table name", Key: targetTableField, DefaultValue: restore.TargetTableName},
+			{Label: "Latest restorable time", Key: latestRestorableField, DefaultValue: strconv.FormatBool(restore.RestoreDateTime == ""), Checkbox: true},
+			{Label: "Restore time (RFC 3339)", Key: restoreDateTimeField, DefaultValue: restoreDateTime},
+		},
+		OnValidate: func(values map[string]string) error {
+			_, err := readRestore(values).Arguments(backups)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			candidate := readRestore(values)
+			arguments, _ := candidate.Arguments(backups)
+			restoreTime := "its latest restorable time"
+			if candidate.RestoreDateTime != "" {
+				restoreTime = strings.TrimSpace(candidate.RestoreDateTime)
+			}
+			confirmBackupAction(
+				fmt.Sprintf("Restore %s as of %s into the new table %s? The restore can take a while and the new table is billed like any table.", restore.SourceTableName, restoreTime, strings.TrimSpace(candidate.TargetTableName)),
+				cmd.UiState.Resource.NewCommand("restore-table-to-point-in-time", arguments...),
+				previousBody,
+				restoredTableMessage,
+				func(commandError string) {
+					showPointInTimeRestoreForm(backups, previousBody, candidate, commandError)
+				},
+			)
+		},
+		OnCancel: func() { restoreBackupView(previousBody) },
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+func restoredTableMessage(output string) (string, error) {
+	name, status, err := dynamodb.ParseRestoredTable(output)
+	return fmt.Sprintf("The new table %s is %s, it is listed once the restore completes.", name, status), err
+}
+
+// confirmBackupAction asks before running a backup or restore command, then shows its result.
+// A failed command calls onError with the error, e.g. to show the form again.
+func confirmBackupAction(question string, command cmd.Command, previousBody tview.Primitive, describe func(output string) (string, error), onError func(commandError string)) {
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: question,
+		LeftChoice: ui.ModalChoice{
+			Name: "Confirm",
+			Handler: func(*tview.Flex) {
+				output, err := command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile)
+				if err != nil {
+					logger.Logger.Error().Err(err).Str("command", command.Name).Msg("Backup command failed")
+					onError(strings.Join(strings.Fields(err.Error()), " "))
+					return
+				}
+				message, err := describe(output)
+				if err != nil {
+					logger.Logger.Warn().Err(err).Str("command", command.Name).Msg("Unexpected output")
+					message = fmt.Sprintf("%s succeeded.", command.Name)
+				}
+				logger.Logger.Info().Msg(message)
+				showBackupResult(message, previousBody)
+			},
+		},
+		RightChoice: ui.ModalChoice{Name: "Cancel", Handler: func(*tview.Flex) { restoreBackupView(previousBody) }},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
+
+// showBackupResult shows the result of a backup or restore command, OK goes back to the view it was started from
+func showBackupResult(message string, previousBody tview.Primitive) {
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: message,
+		LeftChoice: ui.ModalChoice{
+			Name: "OK",
+			Handler: func(*tview.Flex) {
+				// The backups list shows the new backup
+				if isBackupListCommand() {
+					_, body := executeCommand(cmd.UiState.Command)
+					Body = body
+					updateRootView(nil)
+					App.SetFocus(Body)
+					return
+				}
+				restoreBackupView(previousBody)
+			},
+		},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
+
+func restoreBackupView(previousBody tview.Primitive) {
+	Body = previousBody
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
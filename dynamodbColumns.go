@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	commandParser "github.com/cmd-tools/aws-commander/parser"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Form fields of the column chooser, column checkboxes use the column name prefixed with columnField
+const (
+	columnField           = "column."
+	projectionPushDownKey = "__projectionPushDown"
+)
+
+// handleChooseColumns opens the column chooser of the current scan or query
+func handleChooseColumns(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	currentNav := peekNavigation()
+	state := currentQueryState()
+
+	// Every attribute of the page is listed, including the ones hidden by a previous choice
+	command := cmd.UiState.Command
+	applyDynamoDBColumns(&command)
+	command.Parse.Columns = nil
+	columns := commandParser.Columns(currentNav.RowData, command.Parse)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	visible := make(map[string]bool)
+	for _, column := range state.Columns {
+		visible[column] = true
+	}
+
+	var fields []ui.InputField
+	for _, column := range columns {
+		fields = append(fields, ui.InputField{
+			Label:        column,
+			Key:          columnField + column,
+			DefaultValue: fmt.Sprintf("%t", len(state.Columns) == 0 || visible[column]),
+			Checkbox:     true,
+		})
+	}
+	fields = append(fields, ui.InputField{
+		Label:        "Fetch only these attributes (--projection-expression)",
+		Key:          projectionPushDownKey,
+		DefaultValue: fmt.Sprintf("%t", len(state.Projection) > 0),
+		Checkbox:     true,
+	})
+
+	previousBody := Body
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+	}
+
+	selectedColumns := func(values map[string]string) []string {
+		var selected []string
+		for _, column := range columns {
+			if values[columnField+column] == "true" {
+				selected = append(selected, column)
+			}
+		}
+		return selected
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Columns of %s ", cmd.UiState.Command.Name),
+		Fields: fields,
+		OnValidate: func(values map[string]string) error {
+			if len(selectedColumns(values)) == 0 {
+				return fmt.Errorf("select at least one column")
+			}
+			return nil
+		},
+		OnSubmit: func(values map[string]string) {
+			selected := selectedColumns(values)
+			state.Columns = selected
+			if len(selected) == len(columns) {
+				state.Columns = nil
+			}
+
+			if values[projectionPushDownKey] == "true" {
+				state.Projection = projectionWithKeys(selected)
+				runQueryState(state)
+				return
+			}
+			showColumns(state)
+		},
+		OnCancel: restore,
+		Buttons: []ui.FormButton{
+			{
+				Label: "Show all",
+				OnClick: func(values map[string]string) {
+					state.Columns = nil
+					showColumns(state)
+				},
+			},
+		},
+		App: App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+	return nil
+}
+
+// projectionWithKeys adds the table key attributes to the projection, so row actions can still address the items
+func projectionWithKeys(attributes []string) []string {
+	table, err := describeCurrentTable()
+	if err != nil {
+		return attributes
+	}
+
+	projection := append([]string{}, table.TableKeyAttributes()...)
+	for _, attribute := range attributes {
+		if !slices.Contains(projection, attribute) {
+			projection = append(projection, attribute)
+		}
+	}
+	return projection
+}
+
+// showColumns renders the cached result with the chosen columns, fetching again when a projection has to be removed
+func showColumns(state *dynamodb.QueryState) {
+	if len(state.Projection) > 0 || !rerenderCurrentResult() {
+		state.Projection = nil
+		runQueryState(state)
+	}
+}
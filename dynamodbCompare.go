@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the table compare form
+const (
+	compareLeftRegionField     = "__leftRegion"
+	compareRightProfileField   = "__rightProfile"
+	compareRightRegionField    = "__rightRegion"
+	compareRightTableField     = "__rightTable"
+	compareSegmentsField       = "__segments"
+	compareMaxDifferencesField = "__maxDifferences"
+	compareReportFileField     = "__file"
+)
+
+// compareMaxDifferences bounds the differences listed by a table comparison, the others are only counted
+const compareMaxDifferences = 100000
+
+// compareMaxPending bounds the items of a side kept without match, about 150 bytes each, the side ahead waits for the other
+const compareMaxPending = 500000
+
+// isTableCompareReport reports whether the current view lists the differences of two DynamoDB tables
+func isTableCompareReport() bool {
+	currentNav := peekNavigation()
+	return currentNav != nil && currentNav.Type == cmd.BreadcrumbInfoView && dynamoDBState(currentNav).TableCompare != nil
+}
+
+// handleCompareTables opens the compare form of the selected table, the other table defaults to the same name in the same profile
+func handleCompareTables(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	tableName, ok := selectedTableName()
+	if !ok {
+		return event
+	}
+
+	values := map[string]string{
+		compareRightProfileField:   cmd.UiState.Profile,
+		compareRightTableField:     tableName,
+		compareSegmentsField:       "4",
+		compareMaxDifferencesField: "1000",
+	}
+	showCompareTablesForm(tableName, Body, values, "")
+	return nil
+}
+
+// showCompareTablesForm displays the compare form with the given values, and the error of a previous attempt if any
+func showCompareTablesForm(tableName string, previousBody tview.Primitive, values map[string]string, formError string) {
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	profiles := ProfileList.GetProfileNames()
+	if !slices.Contains(profiles, values[compareRightProfileField]) {
+		profiles = append(profiles, values[compareRightProfileField])
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Compare %s/%s with ", cmd.UiState.Profile, tableName),
+		Notes: []string{"Both tables are scanned in full, empty regions use the region of the profile"},
+		Fields: []ui.InputField{
+			{Label: fmt.Sprintf("Region of %s", tableName), Key: compareLeftRegionField, DefaultValue: values[compareLeftRegionField]},
+			{Label: "Profile", Key: compareRightProfileField, DefaultValue: values[compareRightProfileField], Options: profiles},
+			{Label: "Region", Key: compareRightRegionField, DefaultValue: values[compareRightRegionField]},
+			{Label: "Table", Key: compareRightTableField, DefaultValue: values[compareRightTableField]},
+			{Label: fmt.Sprintf("Parallel segments per table (1-%d)", exportMaxSegments), Key: compareSegmentsField, DefaultValue: values[compareSegmentsField]},
+			{Label: fmt.Sprintf("Listed differences (1-%d)", compareMaxDifferences), Key: compareMaxDifferencesField, DefaultValue: values[compareMaxDifferencesField]},
+		},
+		OnValidate: func(values map[string]string) error {
+			_, _, _, _, err := readCompareTablesForm(tableName, values)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			left, right, segments, maxDifferences, _ := readCompareTablesForm(tableName, values)
+
+			keyAttributes, err := compareTableKeys(left, right)
+			if err != nil {
+				showCompareTablesForm(tableName, previousBody, values, strings.Join(strings.Fields(err.Error()), " "))
+				return
+			}
+			startTableCompare(left, right, keyAttributes, segments, maxDifferences, previousBody)
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readCompareTablesForm returns both tables, the number of segments and the number of listed differences
+func readCompareTablesForm(tableName string, values map[string]string) (dynamodb.CompareSide, dynamodb.CompareSide, int, int, error) {
+	left := dynamodb.CompareSide{Profile: cmd.UiState.Profile, Region: strings.TrimSpace(values[compareLeftRegionField]), TableName: tableName}
+	right := dynamodb.CompareSide{
+		Profile:   values[compareRightProfileField],
+		Region:    strings.TrimSpace(values[compareRightRegionField]),
+		TableName: strings.TrimSpace(values[compareRightTableField]),
+	}
+	if right.TableName == "" {
+		return left, right, 0, 0, fmt.Errorf("set the table to compare with")
+	}
+	if left == right {
+		return left, right, 0, 0, fmt.Errorf("choose another table, profile or region to compare with")
+	}
+
+	segments, err := strconv.Atoi(strings.TrimSpace(values[compareSegmentsField]))
+	if err != nil || segments < 1 || segments > exportMaxSegments {
+		return left, right, 0, 0, fmt.Errorf("segments must be a number from 1 to %d", exportMaxSegments)
+	}
+	maxDifferences, err := strconv.Atoi(strings.TrimSpace(values[compareMaxDifferencesField]))
+	if err != nil || maxDifferences < 1 || maxDifferences > compareMaxDifferences {
+		return left, right, 0, 0, fmt.Errorf("listed differences must be a number from 1 to %d", compareMaxDifferences)
+	}
+	return left, right, segments, maxDifferences, nil
+}
+
+// compareTableKeys describes both tables, they must have the same primary key
+func compareTableKeys(left dynamodb.CompareSide, right dynamodb.CompareSide) ([]string, error) {
+	var tables [2]dynamodb.TableDescription
+	for index, side := range []dynamodb.CompareSide{left, right} {
+		output, err := runCompareSideCommand(context.Background(), side, "describe-table")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", side.Label(), err)
+		}
+		if tables[index], err = dynamodb.ParseTableDescription(output); err != nil {
+			return nil, fmt.Errorf("%s: %w", side.Label(), err)
+		}
+	}
+	return dynamodb.CheckCompareKeys(tables[dynamodb.CompareLeft], tables[dynamodb.CompareRight])
+}
+
+// runCompareSideCommand runs a DynamoDB command on the table of a side, with its profile and region
+func runCompareSideCommand(ctx context.Context, side dynamodb.CompareSide, name string, arguments ...string) (string, error) {
+	resource := cmd.UiState.Resource
+	arguments = append([]string{"--table-name", side.TableName}, arguments...)
+	command := resource.NewCommand(name, append(arguments, side.Arguments()...)...)
+	return command.Execute(ctx, resource.Name, side.Profile)
+}
+
+// startTableCompare scans both tables with parallel segments in the background, joining their items on the key while showing progress.
+// ESC cancels both scans, the report is only shown once both tables are read in full.
+func startTableCompare(left dynamodb.CompareSide, right dynamodb.CompareSide, keyAttributes []string, segments int, maxDifferences int, previousBody tview.Primitive) {
+	comparison := dynamodb.NewTableComparison(keyAttributes, maxDifferences, compareMaxPending)
+	capacity := 0.0
+	progress := func(elapsed time.Duration) string {
+		counts := comparison.Counts()
+		waiting := [2]string{}
+		for side := range waiting {
+			if counts.Waiting[side] {
+				waiting[side] = ", waiting for the other table"
+			}
+		}
+		return fmt.Sprintf("Scanning both tables with %d segments each\n\n"+
+			"%s: [gold]%d[white] items, [gold]%d[white] without match yet%s\n"+
+			"%s: [gold]%d[white] items, [gold]%d[white] without match yet%s\n\n"+
+			"Matching: [gold]%d[white]\nDiffering: [gold]%d[white]\nConsumed capacity: [gold]%.1f[white] units\nElapsed: %s\n\n"+
+			"Press [gold]ESC[white] to cancel.",
+			segments,
+			tview.Escape(left.Label()), counts.Scanned[dynamodb.CompareLeft], counts.Pending[dynamodb.CompareLeft], waiting[dynamodb.CompareLeft],
+			tview.Escape(right.Label()), counts.Scanned[dynamodb.CompareRight], counts.Pending[dynamodb.CompareRight], waiting[dynamodb.CompareRight],
+			counts.Matching, counts.Differs, capacity, elapsed.Round(time.Second))
+	}
+
+	scan := func(ctx context.Context, update func(func()), side int, table dynamodb.CompareSide) error {
+		runPage := func(ctx context.Context, segment int, startKey string) (string, error) {
+			arguments := []string{
+				"--segment", strconv.Itoa(segment),
+				"--total-segments", strconv.Itoa(segments),
+				"--return-consumed-capacity", "TOTAL",
+				"--no-paginate",
+				"--cli-read-timeout", scanPageTimeout,
+			}
+			if startKey != "" {
+				arguments = append(arguments, "--exclusive-start-key", startKey)
+			}
+			return runCompareSideCommand(ctx, table, "scan", arguments...)
+		}
+		// Pages are joined as they arrive and dropped, only the digests of the items without match are kept
+		err := dynamodb.ParallelScan(ctx, segments, runPage, func(segment int, page dynamodb.ScanPage) error {
+			if err := comparison.Add(ctx, side, page.Items); err != nil {
+				// Cancelled while waiting for the other side
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf("%s: %w", table.Label(), err)
+			}
+			pageCapacity := page.ConsumedCapacity
+			update(func() { capacity += pageCapacity })
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return comparison.Done(side)
+	}
+
+	var report *dynamodb.CompareReport
+	title := fmt.Sprintf("Comparing %s with %s", left.Label(), right.Label())
+	runBackgroundTask(title, progress, func(ctx context.Context, update func(func())) error {
+		// The first failure cancels the other scan
+		scanCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		scanErrors := make(chan error, 2)
+		go func() { scanErrors <- scan(scanCtx, update, dynamodb.CompareLeft, left) }()
+		go func() { scanErrors <- scan(scanCtx, update, dynamodb.CompareRight, right) }()
+
+		var scanErr error
+		for range 2 {
+			if err := <-scanErrors; err != nil && (scanErr == nil || scanErr == context.Canceled) {
+				scanErr = err
+				cancel()
+			}
+		}
+		if scanErr != nil {
+			return scanErr
+		}
+
+		counts, differences, err := comparison.Finish()
+		if err != nil {
+			return err
+		}
+		report = &dynamodb.CompareReport{Left: left, Right: right, Counts: counts, Differences: differences}
+		return nil
+	}, func(task taskResult) {
+		event := logger.Logger.Info()
+		switch {
+		case task.Err != nil:
+			event = logger.Logger.Error().Err(task.Err)
+		case report != nil:
+			event = event.Str("result", report.Counts.Summary())
+		}
+		event.Str("left", left.Label()).Str("right", right.Label()).Float64("capacity", capacity).Msg(fmt.Sprintf("Table compare %s", task.Status))
+
+		// The user navigated away while comparing, the log keeps the result
+		if !task.Visible {
+			return
+		}
+
+		if report != nil {
+			Body = previousBody
+			showCompareReport(report)
+			return
+		}
+		showTaskResult(fmt.Sprintf("Compare %s after %s, %.1f capacity units consumed.", task.Status, task.Elapsed, capacity), task.Err, previousBody)
+	})
+}
+
+// showCompareReport lists the differences of both tables, Enter opens the diff of the selected item
+func showCompareReport(report *dynamodb.CompareReport) {
+	title := fmt.Sprintf(" %s vs %s: %s ", tview.Escape(report.Left.Label()), tview.Escape(report.Right.Label()), report.Counts.Summary())
+	if listed := len(report.Differences); listed < report.Counts.Total() {
+		title = fmt.Sprintf("%s(first %d listed) ", title, listed)
+	}
+
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+	body := ui.CreateCustomTableView(ui.CustomTableViewProperties{
+		Title:   title,
+		Columns: []ui.Column{{Name: "#"}, {Name: "Status"}, {Name: "Key"}},
+		Rows:    dynamodb.CompareRows(report.Differences),
+		Handler: func(position string) {
+			if index, err := strconv.Atoi(position); err == nil && index >= 1 && index <= len(report.Differences) {
+				showCompareDifference(report, report.Differences[index-1])
+			}
+		},
+		App: App,
+	})
+
+	pushNavigationWithCache(cmd.BreadcrumbInfoView, fmt.Sprintf("Compare %s", report.Right.Label()), "", body)
+	dynamoDBState(peekNavigation()).TableCompare = report
+	Body = body
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
+
+// showCompareDifference reads the item from both tables and shows their diff, a missing item is shown as an empty object
+func showCompareDifference(report *dynamodb.CompareReport, difference dynamodb.CompareDifference) {
+	keyJson, err := json.Marshal(difference.Key)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal item key")
+		return
+	}
+
+	var items [2]interface{}
+	for index, side := range []dynamodb.CompareSide{report.Left, report.Right} {
+		output, err := runCompareSideCommand(context.Background(), side, "get-item", "--key", string(keyJson), "--consistent-read")
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("table", side.Label()).Msg("Unable to read the item")
+			return
+		}
+		item, found, err := dynamodb.ParseGetItem(output)
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("table", side.Label()).Msg("Unable to read the item")
+			return
+		}
+		items[index] = map[string]interface{}{}
+		if found {
+			// The diff viewer takes raw JSON, like the rows of a table
+			itemJson, _ := json.Marshal(item)
+			var data map[string]interface{}
+			if err := json.Unmarshal(itemJson, &data); err == nil {
+				items[index] = data
+			}
+		}
+	}
+
+	plainKey, _ := json.Marshal(difference.Key.Plain())
+	pushNavigation(cmd.BreadcrumbInfoView, fmt.Sprintf("Diff %s", plainKey))
+
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+	Body = ui.CreateJsonDiffViewer(ui.JsonDiffViewerProperties{
+		Title:          fmt.Sprintf("%s %s", difference.Status, plainKey),
+		LeftTitle:      report.Left.Label(),
+		RightTitle:     report.Right.Label(),
+		Left:           items[dynamodb.CompareLeft],
+		Right:          items[dynamodb.CompareRight],
+		DynamoDBFormat: cmd.UiState.ShowDynamoDBJsonFormat,
+	})
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
+
+// handleExportCompareReport asks the file the differences of the compared tables are written to
+func handleExportCompareReport(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isTableCompareReport() {
+		return event
+	}
+
+	report := dynamoDBState(peekNavigation()).TableCompare
+	fileName := fmt.Sprintf("compare-%s-%s-%s.jsonl", report.Left.TableName, report.Right.TableName, time.Now().Format("20060102-150405"))
+	showExportCompareReportForm(report, Body, fileName, "")
+	return nil
+}
+
+// showExportCompareReportForm displays the report export form with the given file, and the error of a previous attempt if any
+func showExportCompareReportForm(report *dynamodb.CompareReport, previousBody tview.Primitive, fileName string, formError string) {
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Export the %d listed differences ", len(report.Differences)),
+		Fields: []ui.InputField{{Label: "File (.jsonl added when missing)", Key: compareReportFileField, DefaultValue: fileName}},
+		OnValidate: func(values map[string]string) error {
+			_, err := readCompareReportFile(values)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			path, _ := readCompareReportFile(values)
+			if err := dynamodb.WriteCompareReport(path, report.Differences); err != nil {
+				showExportCompareReportForm(report, previousBody, values[compareReportFileField], err.Error())
+				return
+			}
+			logger.Logger.Info().Str("file", path).Int("differences", len(report.Differences)).Msg("Compare report exported")
+
+			modal := ui.CreateModal(ui.ModalProperties{
+				Title:      fmt.Sprintf("%d differences written to %s.", len(report.Differences), path),
+				LeftChoice: ui.ModalChoice{Name: "OK", Handler: func(*tview.Flex) { restore() }},
+			}, nil)
+			Body = modal
+			updateRootView(nil)
+			App.SetFocus(modal)
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readCompareReportFile returns the report file, with the .jsonl extension when missing
+func readCompareReportFile(values map[string]string) (string, error) {
+	path := strings.TrimSpace(values[compareReportFileField])
+	if path == "" {
+		return "", fmt.Errorf("set the report file")
+	}
+	if filepath.Ext(path) == "" {
+		path += ".jsonl"
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// handleCountItems counts the items matching the current scan or query, with its key condition and filter
+func handleCountItems(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	tableName, ok := selectedTableName()
+	if !ok {
+		return event
+	}
+
+	// --select COUNT returns no attributes, the projection is left out and pages read up to 1 MB
+	state := *currentQueryState()
+	state.Projection = nil
+	state.PageSize = 0
+	arguments, err := state.Arguments()
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Invalid query expression")
+		return nil
+	}
+	arguments = append([]string{"--table-name", tableName}, arguments...)
+	arguments = append(arguments, "--select", "COUNT", "--return-consumed-capacity", "TOTAL", "--no-paginate", "--cli-read-timeout", scanPageTimeout)
+
+	startCount(tableName, cmd.UiState.Command.Name, arguments, Body)
+	return nil
+}
+
+// startCount runs the scan or query page after page in the background, showing the running totals.
+// ESC cancels it and shows the totals counted so far.
+func startCount(tableName string, commandName string, arguments []string, previousBody tview.Primitive) {
+	resource := cmd.UiState.Resource
+	profileName := cmd.UiState.Profile
+
+	var totals dynamodb.CountTotals
+	progress := func(elapsed time.Duration) string {
+		return fmt.Sprintf("Running %s with --select COUNT\n\nMatching items: [gold]%d[white]\nScanned items: [gold]%d[white]\nPages: %d\nConsumed capacity: [gold]%.1f[white] units\nElapsed: %s\n\nPress [gold]ESC[white] to cancel.",
+			commandName, totals.Count, totals.ScannedCount, totals.Pages, totals.ConsumedCapacity, elapsed.Round(time.Second))
+	}
+
+	runPage := func(ctx context.Context, startKey string) (string, error) {
+		pageArguments := arguments
+		if startKey != "" {
+			pageArguments = append(append([]string{}, arguments...), "--exclusive-start-key", startKey)
+		}
+		command := resource.NewCommand(commandName, pageArguments...)
+		return command.Execute(ctx, resource.Name, profileName)
+	}
+
+	runBackgroundTask(fmt.Sprintf("Counting %s", tableName), progress, func(ctx context.Context, update func(func())) error {
+		// The totals are only read on the UI goroutine through the updates
+		_, err := dynamodb.CountItems(ctx, runPage, func(pageTotals dynamodb.CountTotals) error {
+			update(func() { totals = pageTotals })
+			return nil
+		})
+		return err
+	}, func(task taskResult) {
+		logger.Logger.Info().Err(task.Err).Str("table", tableName).Int("count", totals.Count).Int("scanned", totals.ScannedCount).Float64("capacity", totals.ConsumedCapacity).Msg(fmt.Sprintf("Count %s", task.Status))
+
+		// The user navigated away while counting, the log keeps the result
+		if !task.Visible {
+			return
+		}
+		showTaskResult(fmt.Sprintf("Count %s: %d matching items, %d scanned in %d pages of %s, %.1f capacity units consumed in %s.",
+			task.Status, totals.Count, totals.ScannedCount, totals.Pages, tableName, totals.ConsumedCapacity, task.Elapsed), task.Err, previousBody)
+	})
+}
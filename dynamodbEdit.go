@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/iancoleman/orderedmap"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the item editor
+const (
+	itemJsonField      = "__itemJson"
+	itemWriteModeField = "__itemWriteMode"
+	itemGuardField     = "__itemGuard"
+)
+
+// Ways to write an edited item back
+const (
+	writeModePut    = "put-item (replace the item)"
+	writeModeUpdate = "update-item (changed attributes only)"
+)
+
+// itemEdit is the item of the JSON viewer being edited
+type itemEdit struct {
+	parent         *cmd.NavigationState // Scan or query level holding the item
+	row            int                  // Table row of the item, 1 for the first item
+	original       dynamodb.Item
+	dynamoDBFormat bool // The text is DynamoDB JSON, plain JSON otherwise
+}
+
+// editedItemParent returns the scan or query level and the row of the item shown in the JSON viewer
+func editedItemParent() (*cmd.NavigationState, int, bool) {
+	stackLen := len(cmd.UiState.NavigationStack)
+	if !cmd.UiState.InDynamoDBJsonViewer || stackLen < 2 {
+		return nil, 0, false
+	}
+
+	viewer := cmd.UiState.NavigationStack[stackLen-1]
+	parent := &cmd.UiState.NavigationStack[stackLen-2]
+	// Only scan and query results are edited, a PartiQL statement may return partial items
+	if viewer.Type != cmd.BreadcrumbJsonView || !isDynamoDBItems(cmd.UiState.Command) || parent.Value != cmd.UiState.Command.Name ||
+		(parent.Value != "scan" && parent.Value != "query") {
+		return nil, 0, false
+	}
+	if viewer.Row <= 0 || viewer.Row > len(parent.RowData) {
+		return nil, 0, false
+	}
+	return parent, viewer.Row, true
+}
+
+// handleEditItem opens the item shown in the JSON viewer in the item editor
+func handleEditItem(event *tcell.EventKey) *tcell.EventKey {
+	if _, ok := App.GetFocus().(*tview.TreeView); !ok || isBackgroundTaskRunning() || !canWriteItems() {
+		return event
+	}
+
+	parent, row, ok := editedItemParent()
+	if !ok {
+		return event
+	}
+
+	itemJson, err := json.Marshal(parent.RowData[row-1])
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal the item to edit")
+		return nil
+	}
+	original, err := dynamodb.ParseItem(itemJson)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("The item is not valid DynamoDB JSON")
+		return nil
+	}
+
+	edit := itemEdit{parent: parent, row: row, original: original, dynamoDBFormat: cmd.UiState.ShowDynamoDBJsonFormat}
+	text, err := edit.format(original)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to format the item to edit")
+		return nil
+	}
+
+	showItemEditForm(edit, Body, map[string]string{itemJsonField: text, itemWriteModeField: writeModePut}, "")
+	return nil
+}
+
+// format returns the item as indented JSON in the format of the edit
+func (edit itemEdit) format(item dynamodb.Item) (string, error) {
+	var data interface{} = item
+	if !edit.dynamoDBFormat {
+		data = item.Plain()
+	}
+	text, err := json.MarshalIndent(data, "", "  ")
+	return string(text), err
+}
+
+// parse reads the edited text in the format of the edit
+func (edit itemEdit) parse(text string) (dynamodb.Item, error) {
+	if edit.dynamoDBFormat {
+		return dynamodb.ParseItem([]byte(text))
+	}
+	return dynamodb.ParsePlainItem([]byte(text), edit.original)
+}
+
+// showItemEditForm displays the item editor with the given values, and the error of a previous attempt if any
+func showItemEditForm(edit itemEdit, tableBody tview.Primitive, values map[string]string, formError string) {
+	format := "plain JSON"
+	if edit.dynamoDBFormat {
+		format = "DynamoDB JSON"
+	}
+
+	fields := []ui.InputField{
+		{Label: fmt.Sprintf("Item (%s)", format), Key: itemJsonField, DefaultValue: values[itemJsonField], Multiline: true, Height: 15},
+		{Label: "Write with", Key: itemWriteModeField, DefaultValue: values[itemWriteModeField], Options: []string{writeModePut, writeModeUpdate}},
+		{Label: "Fail if changed since loaded (update-item)", Key: itemGuardField, DefaultValue: values[itemGuardField], Checkbox: true},
+	}
+
+	// Leaving the editor shows the viewer again, the table stays the body behind it
+	restore := func() {
+		Body = tableBody
+		if cmd.UiState.JsonViewerCallback != nil {
+			cmd.UiState.JsonViewerCallback()
+		} else {
+			updateRootView(nil)
+		}
+	}
+
+	var buttons []ui.FormButton
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		buttons = append(buttons, ui.FormButton{
+			Label: "Edit in $EDITOR",
+			OnClick: func(values map[string]string) {
+				text, err := editInExternalEditor(editor, values[itemJsonField])
+				errorText := ""
+				if err != nil {
+					errorText = err.Error()
+				} else {
+					values[itemJsonField] = text
+				}
+				showItemEditForm(edit, tableBody, values, errorText)
+			},
+		})
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Edit item %d of %s ", edit.row, cmd.UiState.SelectedItems[tableNamePlaceHolder]),
+		Fields: fields,
+		OnValidate: func(values map[string]string) error {
+			_, _, err := edit.writeCommand(values)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			command, item, _ := edit.writeCommand(values)
+			if _, err := command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile); err != nil {
+				logger.Logger.Error().Err(err).Str("command", command.Name).Msg("Failed to write the item")
+				showItemEditForm(edit, tableBody, values, strings.Join(strings.Fields(err.Error()), " "))
+				return
+			}
+			edit.apply(item)
+			restore()
+		},
+		OnCancel: restore,
+		Buttons:  buttons,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// writeCommand returns the put-item or update-item command writing the edited item, and the item written
+func (edit itemEdit) writeCommand(values map[string]string) (cmd.Command, dynamodb.Item, error) {
+	item, err := edit.parse(values[itemJsonField])
+	if err != nil {
+		return cmd.Command{}, nil, err
+	}
+
+	// A changed key would write another item, the table must be described to check it
+	table, err := describeCurrentTable()
+	if err != nil {
+		return cmd.Command{}, nil, fmt.Errorf("unable to describe the table, its key attributes are unknown: %w", err)
+	}
+	keyAttributes := table.TableKeyAttributes()
+	if err := dynamodb.CheckKeyUnchanged(edit.original, item, keyAttributes); err != nil {
+		return cmd.Command{}, nil, err
+	}
+
+	// An item fetched with a projection misses attributes, replacing it would drop them
+	query := dynamoDBState(edit.parent).Query
+	projected := query != nil && len(query.Projection) > 0
+	if projected && values[itemWriteModeField] != writeModeUpdate {
+		return cmd.Command{}, nil, fmt.Errorf("the item was fetched with a projection, write it with update-item")
+	}
+
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	if values[itemWriteModeField] == writeModeUpdate {
+		update, err := dynamodb.NewUpdate(edit.original, item, keyAttributes, values[itemGuardField] == "true")
+		if err != nil {
+			return cmd.Command{}, nil, err
+		}
+		arguments, err := update.Arguments()
+		if err != nil {
+			return cmd.Command{}, nil, err
+		}
+		return cmd.UiState.Resource.NewCommand("update-item", append([]string{"--table-name", tableName}, arguments...)...), item, nil
+	}
+
+	itemJson, err := json.Marshal(item)
+	if err != nil {
+		return cmd.Command{}, nil, err
+	}
+	return cmd.UiState.Resource.NewCommand("put-item", "--table-name", tableName, "--item", string(itemJson)), item, nil
+}
+
+// apply replaces the edited item in the rows of the viewer and in the cached result, the table is rendered again on back
+func (edit itemEdit) apply(item dynamodb.Item) {
+	itemJson, err := json.Marshal(item)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal the written item")
+		return
+	}
+	rowData := orderedmap.New()
+	if err := json.Unmarshal(itemJson, rowData); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to decode the written item")
+		return
+	}
+
+	// The viewer reads the row from the same slice
+	edit.parent.RowData[edit.row-1] = *rowData
+
+	updateCachedItems(edit.parent, func(items []interface{}) []interface{} {
+		items[edit.row-1] = *rowData
+		return items
+	})
+}
+
+// updateCachedItems changes the Items of the cached scan or query result, the table is rendered again on back.
+// The items passed to change are the table rows, change is not called when they no longer match.
+func updateCachedItems(nav *cmd.NavigationState, change func(items []interface{}) []interface{}) {
+	result := orderedmap.New()
+	if err := json.Unmarshal([]byte(nav.CachedResult), result); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Unable to update the cached result")
+		return
+	}
+	items, ok := result.Get("Items")
+	itemList, isList := items.([]interface{})
+	if !ok || !isList || len(itemList) != len(nav.RowData) {
+		logger.Logger.Warn().Msg("The cached result does not match the table rows")
+		return
+	}
+	itemList = change(itemList)
+	result.Set("Items", itemList)
+	if _, hasCount := result.Get("Count"); hasCount {
+		result.Set("Count", len(itemList))
+	}
+
+	cachedResult, err := json.Marshal(result)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Unable to update the cached result")
+		return
+	}
+	nav.CachedResult = string(cachedResult)
+	nav.NeedsRender = true
+}
+
+// editInExternalEditor lets the user edit text in the given editor command, suspending the application meanwhile
+func editInExternalEditor(editor string, text string) (string, error) {
+	file, err := os.CreateTemp("", "aws-commander-item-*.json")
+	if err != nil {
+		return text, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return text, err
+	}
+	if err := file.Close(); err != nil {
+		return text, err
+	}
+
+	// EDITOR may hold arguments, e.g. "code --wait"
+	editorArguments := strings.Fields(editor)
+	var runErr error
+	App.Suspend(func() {
+		command := exec.Command(editorArguments[0], append(editorArguments[1:], file.Name())...)
+		command.Stdin, command.Stdout, command.Stderr = os.Stdin, os.Stdout, os.Stderr
+		runErr = command.Run()
+	})
+	if runErr != nil {
+		return text, fmt.Errorf("%s failed: %w", editor, runErr)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return text, err
+	}
+	return string(edited), nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the export form
+const (
+	exportFileField     = "__file"
+	exportFormatField   = "__format"
+	exportSegmentsField = "__segments"
+)
+
+// exportMaxSegments bounds the parallel scan workers, each one runs its own AWS CLI process
+const exportMaxSegments = 32
+
+// scanPageTimeout is the read timeout of a scan or query page in seconds, a page reads up to 1 MB
+const scanPageTimeout = "20"
+
+// isDynamoDBTableList reports whether the current view lists the DynamoDB tables
+func isDynamoDBTableList() bool {
+	if cmd.UiState.Resource.Name != "dynamodb" || cmd.UiState.Command.Name != "list-tables" {
+		return false
+	}
+	currentNav := peekNavigation()
+	return currentNav != nil && currentNav.Value == cmd.UiState.Command.Name && currentNav.Type == cmd.BreadcrumbCommand
+}
+
+// selectedTableName returns the selected table of the list, or the table of the index list, scan or query
+func selectedTableName() (string, bool) {
+	if isDynamoDBItemsCommand() || isDescribeTableCommand() {
+		tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+		return tableName, tableName != ""
+	}
+
+	table, ok := Body.(*tview.Table)
+	if !ok || !isDynamoDBTableList() {
+		return "", false
+	}
+	row, _ := table.GetSelection()
+	if row <= 0 {
+		return "", false
+	}
+	tableName := table.GetCell(row, 0).Text
+	return tableName, tableName != ""
+}
+
+// handleExportTable opens the export form of the selected table
+func handleExportTable(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	tableName, ok := selectedTableName()
+	if !ok {
+		return event
+	}
+
+	values := map[string]string{
+		exportFileField:     fmt.Sprintf("%s-%s", tableName, time.Now().Format("20060102-150405")),
+		exportFormatField:   dynamodb.ExportJsonLinesDynamoDB,
+		exportSegmentsField: "4",
+	}
+	showExportForm(tableName, Body, values, "")
+	return nil
+}
+
+// showExportForm displays the export form with the given values, and the error of a previous attempt if any
+func showExportForm(tableName string, previousBody tview.Primitive, values map[string]string, formError string) {
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Export %s ", tableName),
+		Fields: []ui.InputField{
+			{Label: "File (.jsonl or .csv added when missing)", Key: exportFileField, DefaultValue: values[exportFileField]},
+			{Label: "Format", Key: exportFormatField, DefaultValue: values[exportFormatField], Options: dynamodb.ExportFormats},
+			{Label: fmt.Sprintf("Parallel segments (1-%d)", exportMaxSegments), Key: exportSegmentsField, DefaultValue: values[exportSegmentsField]},
+		},
+		OnValidate: func(values map[string]string) error {
+			_, _, err := readExportForm(values)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			path, segments, _ := readExportForm(values)
+
+			var keyColumns []string
+			if table, err := describeTable(tableName); err == nil {
+				keyColumns = table.TableKeyAttributes()
+			} else {
+				logger.Logger.Debug().Err(err).Msg("Unable to describe table, CSV columns are sorted by name only")
+			}
+
+			writer, err := dynamodb.NewExportWriter(path, values[exportFormatField], keyColumns)
+			if err != nil {
+				showExportForm(tableName, previousBody, values, err.Error())
+				return
+			}
+			startExport(tableName, path, segments, writer, previousBody)
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readExportForm returns the export file, with the extension of the format when missing, and the number of segments
+func readExportForm(values map[string]string) (string, int, error) {
+	path := strings.TrimSpace(values[exportFileField])
+	if path == "" {
+		return "", 0, fmt.Errorf("set the export file")
+	}
+	if filepath.Ext(path) == "" {
+		if values[exportFormatField] == dynamodb.ExportCsv {
+			path += ".csv"
+		} else {
+			path += ".jsonl"
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", 0, fmt.Errorf("%s already exists", path)
+	}
+
+	segments, err := strconv.Atoi(strings.TrimSpace(values[exportSegmentsField]))
+	if err != nil || segments < 1 || segments > exportMaxSegments {
+		return "", 0, fmt.Errorf("segments must be a number from 1 to %d", exportMaxSegments)
+	}
+	return path, segments, nil
+}
+
+// startExport scans the table with parallel segments in the background, writing the items while showing progress.
+// ESC cancels the scan, the file keeps the items exported so far.
+func startExport(tableName string, path string, segments int, writer *dynamodb.ExportWriter, previousBody tview.Primitive) {
+	resource := cmd.UiState.Resource
+	profileName := cmd.UiState.Profile
+
+	items := 0
+	capacity := 0.0
+	progress := func(elapsed time.Duration) string {
+		return fmt.Sprintf("Scanning with %d segments into %s\n\nItems: [gold]%d[white]\nConsumed capacity: [gold]%.1f[white] units\nElapsed: %s\n\nPress [gold]ESC[white] to cancel and keep the items exported so far.",
+			segments, path, items, capacity, elapsed.Round(time.Second))
+	}
+
+	runPage := func(ctx context.Context, segment int, startKey string) (string, error) {
+		arguments := []string{
+			"--table-name", tableName,
+			"--segment", strconv.Itoa(segment),
+			"--total-segments", strconv.Itoa(segments),
+			"--return-consumed-capacity", "TOTAL",
+			"--no-paginate",
+			"--cli-read-timeout", scanPageTimeout,
+		}
+		if startKey != "" {
+			arguments = append(arguments, "--exclusive-start-key", startKey)
+		}
+		command := resource.NewCommand("scan", arguments...)
+		return command.Execute(ctx, resource.Name, profileName)
+	}
+
+	runBackgroundTask(fmt.Sprintf("Exporting %s", tableName), progress, func(ctx context.Context, update func(func())) error {
+		// Pages arrive one at a time, the counters are only read on the UI goroutine through the updates
+		scanErr := dynamodb.ParallelScan(ctx, segments, runPage, func(segment int, page dynamodb.ScanPage) error {
+			if err := writer.Write(page.Items); err != nil {
+				return err
+			}
+			pageItems, pageCapacity := len(page.Items), page.ConsumedCapacity
+			update(func() {
+				items += pageItems
+				capacity += pageCapacity
+			})
+			return nil
+		})
+		// A cancelled export keeps a valid file, unless it can not be closed
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		return scanErr
+	}, func(task taskResult) {
+		event := logger.Logger.Info()
+		if task.Err != nil {
+			event = logger.Logger.Error().Err(task.Err)
+		}
+		event.Str("table", tableName).Str("file", path).Int("items", items).Float64("capacity", capacity).Msg(fmt.Sprintf("Export %s", task.Status))
+
+		// The user navigated away while exporting, the log keeps the result
+		if !task.Visible {
+			return
+		}
+		showTaskResult(fmt.Sprintf("Export %s: %d items written to %s in %s, %.1f capacity units consumed.",
+			task.Status, items, path, task.Elapsed, capacity), task.Err, previousBody)
+	})
+}
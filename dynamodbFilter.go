@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the filter builder, condition fields are suffixed with the condition index
+const (
+	filterAttributeField     = "attribute."
+	filterOperatorField      = "operator."
+	filterTypeField          = "type."
+	filterValueField         = "value."
+	filterRawExpressionField = "rawExpression"
+	filterRawNamesField      = "rawNames"
+	filterRawValuesField     = "rawValues"
+)
+
+// isDynamoDBItemsCommand reports whether the current view shows the items of a DynamoDB scan or query
+func isDynamoDBItemsCommand() bool {
+	if cmd.UiState.Resource.Name != "dynamodb" || (cmd.UiState.Command.Name != "scan" && cmd.UiState.Command.Name != "query") {
+		return false
+	}
+	currentNav := peekNavigation()
+	return currentNav != nil && currentNav.Value == cmd.UiState.Command.Name &&
+		(currentNav.Type == cmd.BreadcrumbCommand || currentNav.Type == cmd.BreadcrumbDependentCmd)
+}
+
+// handleFilterItems opens the filter builder of the current scan or query
+func handleFilterItems(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	state := currentQueryState()
+	conditions := state.Filter.Conditions
+	if len(conditions) == 0 {
+		conditions = []dynamodb.FilterCondition{{Operator: dynamodb.FilterEqual, Type: "S"}}
+	}
+
+	showFilterForm(state, Body, dynamodb.Filter{
+		Conditions:    conditions,
+		RawExpression: state.Filter.RawExpression,
+		RawNames:      state.Filter.RawNames,
+		RawValues:     state.Filter.RawValues,
+	})
+	return nil
+}
+
+// showFilterForm displays the filter builder. Adding or removing a condition rebuilds the form with the values typed so far.
+func showFilterForm(state *dynamodb.QueryState, previousBody tview.Primitive, filter dynamodb.Filter) {
+	var fields []ui.InputField
+	for index, condition := range filter.Conditions {
+		suffix := strconv.Itoa(index)
+		fields = append(fields,
+			ui.InputField{Label: fmt.Sprintf("Attribute %d", index+1), Key: filterAttributeField + suffix, DefaultValue: condition.Attribute},
+			ui.InputField{Label: "Operator", Key: filterOperatorField + suffix, DefaultValue: condition.Operator, Options: dynamodb.FilterOperators},
+			ui.InputField{Label: "Type", Key: filterTypeField + suffix, DefaultValue: condition.Type, Options: dynamodb.FilterValueTypes},
+			ui.InputField{Label: "Value (IN: a,b,c / size: > 3)", Key: filterValueField + suffix, DefaultValue: condition.Value},
+		)
+	}
+	fields = append(fields,
+		ui.InputField{Label: "Raw filter expression", Key: filterRawExpressionField, DefaultValue: filter.RawExpression},
+		ui.InputField{Label: "Raw attribute names (JSON)", Key: filterRawNamesField, DefaultValue: filter.RawNames},
+		ui.InputField{Label: "Raw attribute values (JSON)", Key: filterRawValuesField, DefaultValue: filter.RawValues},
+	)
+
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Filter %s ", cmd.UiState.Command.Name),
+		Fields: fields,
+		OnValidate: func(values map[string]string) error {
+			candidate := *state
+			candidate.Filter = readFilterForm(values, len(filter.Conditions), true)
+			_, err := candidate.Arguments()
+			return err
+		},
+		OnSubmit: func(values map[string]string) {
+			state.Filter = readFilterForm(values, len(filter.Conditions), true)
+			runQueryState(state)
+		},
+		OnCancel: restore,
+		Buttons: []ui.FormButton{
+			{
+				Label: "Add condition",
+				OnClick: func(values map[string]string) {
+					updated := readFilterForm(values, len(filter.Conditions), false)
+					updated.Conditions = append(updated.Conditions, dynamodb.FilterCondition{Operator: dynamodb.FilterEqual, Type: "S"})
+					showFilterForm(state, previousBody, updated)
+				},
+			},
+			{
+				Label: "Remove condition",
+				OnClick: func(values map[string]string) {
+					updated := readFilterForm(values, len(filter.Conditions), false)
+					if len(updated.Conditions) > 0 {
+						updated.Conditions = updated.Conditions[:len(updated.Conditions)-1]
+					}
+					showFilterForm(state, previousBody, updated)
+				},
+			},
+		},
+		App: App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readFilterForm reads the filter from the form values. Conditions without attribute are dropped when skipEmpty is set.
+func readFilterForm(values map[string]string, conditionCount int, skipEmpty bool) dynamodb.Filter {
+	filter := dynamodb.Filter{
+		RawExpression: values[filterRawExpressionField],
+		RawNames:      values[filterRawNamesField],
+		RawValues:     values[filterRawValuesField],
+	}
+
+	for index := 0; index < conditionCount; index++ {
+		suffix := strconv.Itoa(index)
+		condition := dynamodb.FilterCondition{
+			Attribute: values[filterAttributeField+suffix],
+			Operator:  values[filterOperatorField+suffix],
+			Type:      values[filterTypeField+suffix],
+			Value:     values[filterValueField+suffix],
+		}
+		if skipEmpty && condition.Attribute == "" {
+			continue
+		}
+		filter.Conditions = append(filter.Conditions, condition)
+	}
+
+	return filter
+}
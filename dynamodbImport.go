@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the import form
+const (
+	importFileField   = "__file"
+	importFormatField = "__format"
+	importDryRunField = "__dryRun"
+)
+
+// Retries of the UnprocessedItems of a batch, waiting 200ms, 400ms, 800ms... in between
+const (
+	importMaxAttempts = 6
+	importBackoff     = 200 * time.Millisecond
+)
+
+// importShownErrors is how many invalid lines the import reports
+const importShownErrors = 5
+
+// handleImportItems opens the import form of the selected table
+func handleImportItems(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !canWriteItems() {
+		return event
+	}
+
+	tableName, ok := selectedTableName()
+	if !ok {
+		return event
+	}
+
+	values := map[string]string{importFormatField: dynamodb.ExportJsonLinesDynamoDB, importDryRunField: "true"}
+	showImportForm(tableName, Body, values, "")
+	return nil
+}
+
+// showImportForm displays the import form with the given values, and the error of a previous attempt if any
+func showImportForm(tableName string, previousBody tview.Primitive, values map[string]string, formError string) {
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Import into %s ", tableName),
+		Fields: []ui.InputField{
+			{Label: "File", Key: importFileField, DefaultValue: values[importFileField]},
+			{Label: "Format", Key: importFormatField, DefaultValue: values[importFormatField], Options: dynamodb.ExportFormats},
+			{Label: "Dry run (validate only)", Key: importDryRunField, DefaultValue: values[importDryRunField], Checkbox: true},
+		},
+		OnValidate: func(values map[string]string) error {
+			if strings.TrimSpace(values[importFileField]) == "" {
+				return fmt.Errorf("set the file to import")
+			}
+			return nil
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			// The whole file is checked before anything is written
+			items, err := readImportFile(tableName, strings.TrimSpace(values[importFileField]), values[importFormatField])
+			if err != nil {
+				showImportForm(tableName, previousBody, values, strings.Join(strings.Fields(err.Error()), " "))
+				return
+			}
+
+			if values[importDryRunField] == "true" {
+				showImportResult(fmt.Sprintf("Dry run: the %d items are valid and can be imported into %s.", len(items), tableName), previousBody, false)
+				return
+			}
+			startImport(tableName, items, strings.TrimSpace(values[importFileField]), previousBody)
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readImportFile reads and checks every item of the file against the table
+func readImportFile(tableName string, path string, format string) ([]dynamodb.Item, error) {
+	table, err := describeTable(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe the table, its key attributes are unknown: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	items, invalid, err := dynamodb.ReadImportItems(file, format, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(invalid) > 0 {
+		var details []string
+		for _, importError := range invalid[:min(len(invalid), importShownErrors)] {
+			details = append(details, importError.Error())
+		}
+		return nil, fmt.Errorf("%d invalid lines, nothing was imported: %s", len(invalid), strings.Join(details, "; "))
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s has no items", path)
+	}
+	return items, nil
+}
+
+// startImport writes the items with batch-write-item in the background while showing progress.
+// Rejected items go to a reject file next to the imported one, ESC cancels the remaining batches.
+func startImport(tableName string, items []dynamodb.Item, path string, previousBody tview.Primitive) {
+	resource := cmd.UiState.Resource
+	profileName := cmd.UiState.Profile
+	rejectPath := fmt.Sprintf("%s.rejected-%s.jsonl", strings.TrimSuffix(path, filepath.Ext(path)), time.Now().Format("20060102-150405"))
+
+	written, rejected := 0, 0
+	progress := func(elapsed time.Duration) string {
+		return fmt.Sprintf("Writing %d items from %s\n\nWritten: [gold]%d[white]\nRejected: [gold]%d[white]\nElapsed: %s\n\nPress [gold]ESC[white] to cancel the remaining batches.",
+			len(items), path, written, rejected, elapsed.Round(time.Second))
+	}
+
+	writer := dynamodb.BatchWriter{
+		TableName: tableName,
+		Run: func(ctx context.Context, requestItems string) (string, error) {
+			command := resource.NewCommand("batch-write-item", "--request-items", requestItems)
+			return command.Execute(ctx, resource.Name, profileName)
+		},
+		MaxAttempts: importMaxAttempts,
+		Backoff:     importBackoff,
+	}
+
+	var firstReject error
+	runBackgroundTask(fmt.Sprintf("Importing into %s", tableName), progress, func(ctx context.Context, update func(func())) error {
+		// The reject file is created with the first rejected item, in the DynamoDB JSON format the import reads
+		var rejects *dynamodb.ExportWriter
+		importErr := writer.Write(ctx, items, func(batchWritten int, batchRejected []dynamodb.RejectedItem) error {
+			if len(batchRejected) > 0 {
+				if rejects == nil {
+					var err error
+					if rejects, err = dynamodb.NewExportWriter(rejectPath, dynamodb.ExportJsonLinesDynamoDB, nil); err != nil {
+						return err
+					}
+				}
+				for _, reject := range batchRejected {
+					logger.Logger.Warn().Err(reject.Err).Str("table", tableName).Msg("Item rejected by the import")
+					if err := rejects.Write([]dynamodb.Item{reject.Item}); err != nil {
+						return err
+					}
+				}
+				if firstReject == nil {
+					firstReject = batchRejected[0].Err
+				}
+			}
+
+			batchRejectedCount := len(batchRejected)
+			update(func() {
+				written += batchWritten
+				rejected += batchRejectedCount
+			})
+			return nil
+		})
+		if rejects != nil {
+			if err := rejects.Close(); err != nil && importErr == nil {
+				importErr = err
+			}
+		}
+		return importErr
+	}, func(task taskResult) {
+		logger.Logger.Info().Err(task.Err).Str("table", tableName).Int("written", written).Int("rejected", rejected).Msg(fmt.Sprintf("Import %s", task.Status))
+
+		// The user navigated away while importing, the log keeps the result
+		if !task.Visible {
+			return
+		}
+
+		message := fmt.Sprintf("Import %s: %d of %d items written to %s in %s.", task.Status, written, len(items), tableName, task.Elapsed)
+		if rejected > 0 {
+			message = fmt.Sprintf("%s\n\n%d items rejected, written to %s as DynamoDB JSON lines. First error: %s", message, rejected, rejectPath, strings.Join(strings.Fields(firstReject.Error()), " "))
+		}
+		if task.Err != nil {
+			message = fmt.Sprintf("%s\n\n%s", message, strings.Join(strings.Fields(task.Err.Error()), " "))
+		}
+		showImportResult(message, previousBody, written > 0)
+	})
+}
+
+// showImportResult shows the outcome of an import, scan and query results are fetched again when items were written
+func showImportResult(message string, previousBody tview.Primitive, refresh bool) {
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: message,
+		LeftChoice: ui.ModalChoice{
+			Name: "OK",
+			Handler: func(*tview.Flex) {
+				Body = previousBody
+				if !refresh || !isDynamoDBItemsCommand() || !runQueryState(currentQueryState()) {
+					updateRootView(nil)
+				}
+				App.SetFocus(Body)
+			},
+		},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
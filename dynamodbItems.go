@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the new item form, key fields use the attribute name prefixed with newItemKeyField
+const (
+	newItemKeyField        = "key."
+	newItemAttributesField = "__attributes"
+	newItemNotExistsField  = "__notExists"
+)
+
+// canWriteItems reports whether the current profile may change DynamoDB items
+func canWriteItems() bool {
+	if cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+		logger.Logger.Warn().Str("profile", cmd.UiState.Profile).Msg("The profile is read-only, items can not be changed")
+		return false
+	}
+	return true
+}
+
+// handleNewItem opens the new item form of the current table, with one field per key attribute
+func handleNewItem(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() || !canWriteItems() {
+		return event
+	}
+
+	table, err := describeCurrentTable()
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Unable to describe the table, its key attributes are unknown")
+		return nil
+	}
+
+	values := map[string]string{newItemAttributesField: "{}", newItemNotExistsField: "true"}
+	showNewItemForm(table, Body, values, "")
+	return nil
+}
+
+// showNewItemForm displays the new item form with the given values, and the error of a previous attempt if any
+func showNewItemForm(table dynamodb.TableDescription, tableBody tview.Primitive, values map[string]string, formError string) {
+	format := "plain JSON"
+	if cmd.UiState.ShowDynamoDBJsonFormat {
+		format = "DynamoDB JSON"
+	}
+
+	var fields []ui.InputField
+	for index, name := range table.TableKeyAttributes() {
+		keyType := "partition key"
+		if index > 0 {
+			keyType = "sort key"
+		}
+		fields = append(fields, ui.InputField{
+			Label:        fmt.Sprintf("%s (%s, %s)", name, table.AttributeType(name), keyType),
+			Key:          newItemKeyField + name,
+			DefaultValue: values[newItemKeyField+name],
+		})
+	}
+	fields = append(fields,
+		ui.InputField{Label: fmt.Sprintf("Other attributes (%s)", format), Key: newItemAttributesField, DefaultValue: values[newItemAttributesField], Multiline: true, Height: 10},
+		ui.InputField{Label: "Fail if the item exists", Key: newItemNotExistsField, DefaultValue: values[newItemNotExistsField], Checkbox: true},
+	)
+
+	restore := func() {
+		Body = tableBody
+		updateRootView(nil)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" New item in %s ", table.TableName),
+		Fields: fields,
+		OnValidate: func(values map[string]string) error {
+			_, err := newItemCommand(table, values)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			command, _ := newItemCommand(table, values)
+			if _, err := command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile); err != nil {
+				logger.Logger.Error().Err(err).Msg("Failed to create the item")
+				showNewItemForm(table, tableBody, values, strings.Join(strings.Fields(err.Error()), " "))
+				return
+			}
+
+			// The item may not match the key condition or filter, running again shows it only where it belongs
+			Body = tableBody
+			runQueryState(currentQueryState())
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// newItemCommand returns the put-item command creating the item of the form
+func newItemCommand(table dynamodb.TableDescription, values map[string]string) (cmd.Command, error) {
+	var item dynamodb.Item
+	var err error
+	if cmd.UiState.ShowDynamoDBJsonFormat {
+		item, err = dynamodb.ParseItem([]byte(values[newItemAttributesField]))
+	} else {
+		item, err = dynamodb.ParsePlainItem([]byte(values[newItemAttributesField]), nil)
+	}
+	if err != nil {
+		return cmd.Command{}, fmt.Errorf("attributes: %w", err)
+	}
+
+	keyAttributes := table.TableKeyAttributes()
+	for _, name := range keyAttributes {
+		if _, exists := item[name]; exists {
+			return cmd.Command{}, fmt.Errorf("set the key attribute %s in its own field", name)
+		}
+		value, err := dynamodb.NewKeyValue(table.AttributeType(name), values[newItemKeyField+name])
+		if err != nil {
+			return cmd.Command{}, fmt.Errorf("%s: %w", name, err)
+		}
+		item[name] = value
+	}
+
+	itemJson, err := json.Marshal(item)
+	if err != nil {
+		return cmd.Command{}, err
+	}
+	arguments := []string{"--table-name", table.TableName, "--item", string(itemJson)}
+	if values[newItemNotExistsField] == "true" && len(keyAttributes) > 0 {
+		arguments = append(arguments,
+			"--condition-expression", "attribute_not_exists(#pk)",
+			"--expression-attribute-names", fmt.Sprintf(`{"#pk": %q}`, keyAttributes[0]))
+	}
+	return cmd.UiState.Resource.NewCommand("put-item", arguments...), nil
+}
+
+// handleDeleteItem deletes the selected item after a confirmation, its key is taken from the row data
+func handleDeleteItem(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() || !canWriteItems() {
+		return event
+	}
+
+	row, rowData, ok := getSelectedRowData()
+	if !ok {
+		return nil
+	}
+
+	table, err := describeCurrentTable()
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Unable to describe the table, its key attributes are unknown")
+		return nil
+	}
+
+	rowJson, err := json.Marshal(rowData)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal selected row")
+		return nil
+	}
+	item, err := dynamodb.ParseItem(rowJson)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("The selected row is not a DynamoDB item")
+		return nil
+	}
+	key, err := item.Key(table.TableKeyAttributes())
+	if err != nil {
+		// e.g. a projection without the key attributes
+		logger.Logger.Error().Err(err).Msg("Unable to build the key of the selected item")
+		return nil
+	}
+	keyJson, err := json.Marshal(key)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal the item key")
+		return nil
+	}
+
+	command := cmd.UiState.Resource.NewCommand("delete-item", "--table-name", table.TableName, "--key", string(keyJson))
+	confirmDeleteItem(command, row, string(keyJson), Body, "")
+	return nil
+}
+
+// confirmDeleteItem asks before running delete-item, showing the error of a previous attempt if any
+func confirmDeleteItem(command cmd.Command, row int, keyJson string, tableBody tview.Primitive, deleteError string) {
+	restore := func(*tview.Flex) {
+		Body = tableBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	question := fmt.Sprintf("Delete the item %s from %s?", keyJson, cmd.UiState.SelectedItems[tableNamePlaceHolder])
+	if deleteError != "" {
+		question = fmt.Sprintf("Delete failed: %s\n\n%s", deleteError, question)
+	}
+
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: question,
+		LeftChoice: ui.ModalChoice{
+			Name: "Delete",
+			Handler: func(flex *tview.Flex) {
+				if _, err := command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile); err != nil {
+					logger.Logger.Error().Err(err).Msg("Failed to delete the item")
+					confirmDeleteItem(command, row, keyJson, tableBody, strings.Join(strings.Fields(err.Error()), " "))
+					return
+				}
+
+				// The item leaves the cached page, without fetching it again
+				Body = tableBody
+				updateCachedItems(peekNavigation(), func(items []interface{}) []interface{} {
+					return append(items[:row-1], items[row:]...)
+				})
+				if !rerenderCurrentResult() {
+					restore(flex)
+					return
+				}
+				App.SetFocus(Body)
+			},
+		},
+		RightChoice: ui.ModalChoice{Name: "Cancel", Handler: restore},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
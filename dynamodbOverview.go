@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+)
+
+// isDescribeTableCommand reports whether the current view lists the indexes of a DynamoDB table
+func isDescribeTableCommand() bool {
+	if cmd.UiState.Resource.Name != "dynamodb" || cmd.UiState.Command.Name != "describe-table" {
+		return false
+	}
+	currentNav := peekNavigation()
+	return currentNav != nil && currentNav.Value == cmd.UiState.Command.Name &&
+		(currentNav.Type == cmd.BreadcrumbCommand || currentNav.Type == cmd.BreadcrumbDependentCmd)
+}
+
+// handleTableOverview shows the metadata of the selected table, with its time to live and backup settings
+func handleTableOverview(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	tableName, ok := selectedTableName()
+	if !ok {
+		return event
+	}
+
+	// Counts and statuses change, the overview always describes the table again
+	forgetTableDescription(tableName)
+	table, err := describeTable(tableName)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("table", tableName).Msg("Unable to describe the table")
+		return nil
+	}
+
+	overview := dynamodb.TableOverview{Table: table}
+	if output, err := runTableCommand("describe-time-to-live", tableName); err != nil {
+		overview.TimeToLiveError = err
+	} else if timeToLive, err := dynamodb.ParseTimeToLive(output); err != nil {
+		overview.TimeToLiveError = err
+	} else {
+		overview.TimeToLive = &timeToLive
+	}
+	if output, err := runTableCommand("describe-continuous-backups", tableName); err != nil {
+		overview.ContinuousBackupsError = err
+	} else if backups, err := dynamodb.ParseContinuousBackups(output); err != nil {
+		overview.ContinuousBackupsError = err
+	} else {
+		overview.ContinuousBackups = &backups
+	}
+
+	rows := overview.Rows()
+	pushNavigation(cmd.BreadcrumbInfoView, fmt.Sprintf("Overview %s", tableName))
+
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+	Body = ui.CreateCustomTableView(ui.CustomTableViewProperties{
+		Title:   fmt.Sprintf(" Overview of %s ", tableName),
+		Columns: []ui.Column{{Name: "Section"}, {Name: "Property"}, {Name: "Value"}},
+		Rows:    rows,
+		Handler: func(string) {},
+		App:     App,
+	})
+	updateRootView(nil)
+	App.SetFocus(Body)
+	return nil
+}
+
+// runTableCommand runs a DynamoDB command taking the table name as only argument
+func runTableCommand(name string, tableName string) (string, error) {
+	command := cmd.UiState.Resource.NewCommand(name, "--table-name", tableName)
+	return command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the PartiQL editor
+const (
+	statementField           = "__statement"
+	statementParametersField = "__parameters"
+	statementHistoryField    = "__history"
+)
+
+// statementHistoryStateName is the local state file keeping the PartiQL history between sessions
+const statementHistoryStateName = "partiql-history"
+
+// statementHistory is loaded from the local state on first use
+var statementHistory dynamodb.StatementHistory
+
+func loadStatementHistory() dynamodb.StatementHistory {
+	if statementHistory == nil {
+		statementHistory = dynamodb.StatementHistory{}
+		if err := cmd.LoadLocalState(statementHistoryStateName, &statementHistory); err != nil {
+			logger.Logger.Warn().Err(err).Msg("Unable to load the PartiQL history")
+		}
+	}
+	return statementHistory
+}
+
+// isPartiqlCommand reports whether the current view shows the results of a PartiQL statement
+func isPartiqlCommand() bool {
+	currentNav := peekNavigation()
+	return cmd.UiState.Command.RequiresStatementInput && currentNav != nil && currentNav.Value == cmd.UiState.Command.Name &&
+		(currentNav.Type == cmd.BreadcrumbCommand || currentNav.Type == cmd.BreadcrumbDependentCmd)
+}
+
+// handleEditStatement opens the PartiQL editor with the statement of the current results
+func handleEditStatement(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isPartiqlCommand() {
+		return event
+	}
+	showStatementForm()
+	return nil
+}
+
+// showStatementForm displays the PartiQL editor, starting from the last statement run at this level or on the table
+func showStatementForm() {
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	statement := dynamodb.Statement{Statement: fmt.Sprintf("SELECT * FROM \"%s\"", tableName)}
+	if currentNav := peekNavigation(); currentNav != nil && dynamoDBState(currentNav).Statement != nil {
+		statement = *dynamoDBState(currentNav).Statement
+	} else if history := loadStatementHistory().Statements(cmd.UiState.Profile, tableName); len(history) > 0 {
+		statement.Statement = history[0]
+	}
+
+	showStatementFormWith(statement)
+}
+
+// showStatementFormWith displays the PartiQL editor with the given statement
+func showStatementFormWith(statement dynamodb.Statement) {
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+
+	fields := []ui.InputField{
+		{Label: "Statement", Key: statementField, DefaultValue: statement.Statement, Multiline: true, Height: 8},
+		{Label: "Parameters (JSON list, optional)", Key: statementParametersField, DefaultValue: statement.Parameters},
+	}
+
+	// History entries are shown on one line, the form maps them back to the statements
+	history := loadStatementHistory().Statements(cmd.UiState.Profile, tableName)
+	historyStatements := make(map[string]string)
+	var historyOptions []string
+	for _, previous := range history {
+		option := strings.Join(strings.Fields(previous), " ")
+		if _, exists := historyStatements[option]; !exists {
+			historyStatements[option] = previous
+			historyOptions = append(historyOptions, option)
+		}
+	}
+
+	var buttons []ui.FormButton
+	if len(historyOptions) > 0 {
+		fields = append(fields, ui.InputField{Label: "History", Key: statementHistoryField, Options: historyOptions})
+		buttons = append(buttons, ui.FormButton{
+			Label: "Load from history",
+			OnClick: func(values map[string]string) {
+				showStatementFormWith(dynamodb.Statement{
+					Statement:  historyStatements[values[statementHistoryField]],
+					Parameters: values[statementParametersField],
+				})
+			},
+		})
+	}
+
+	readStatement := func(values map[string]string) dynamodb.Statement {
+		return dynamodb.Statement{Statement: values[statementField], Parameters: values[statementParametersField]}
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" PartiQL on %s ", tableName),
+		Fields: fields,
+		OnValidate: func(values map[string]string) error {
+			statement := readStatement(values)
+			if _, err := statement.Arguments(); err != nil {
+				return err
+			}
+			if !statement.IsRead() && cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+				return fmt.Errorf("the profile is read-only, only SELECT statements can run")
+			}
+			return nil
+		},
+		OnSubmit: func(values map[string]string) {
+			statement := readStatement(values)
+			if statement.IsRead() {
+				runStatement(statement)
+				return
+			}
+			confirmWriteStatement(statement)
+		},
+		OnCancel: func() {
+			// Without results yet, cancelling leaves the command
+			if currentNav := peekNavigation(); currentNav != nil && currentNav.CachedBody != nil {
+				Body = currentNav.CachedBody
+			} else {
+				handleDependentCommandBack()
+			}
+			updateRootView(nil)
+			App.SetFocus(Body)
+		},
+		Buttons: buttons,
+		App:     App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// confirmWriteStatement asks before running an INSERT, UPDATE or DELETE statement, cancelling goes back to the editor
+func confirmWriteStatement(statement dynamodb.Statement) {
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: fmt.Sprintf("Run this %s statement on %s?\n\n%s", statement.Keyword(), cmd.UiState.SelectedItems[tableNamePlaceHolder],
+			strings.Join(strings.Fields(statement.Statement), " ")),
+		LeftChoice: ui.ModalChoice{
+			Name:    "Run",
+			Handler: func(*tview.Flex) { runStatement(statement) },
+		},
+		RightChoice: ui.ModalChoice{
+			Name:    "Cancel",
+			Handler: func(*tview.Flex) { showStatementFormWith(statement) },
+		},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
+
+// runStatement runs execute-statement with the statement, remembering it in the history of the table
+func runStatement(statement dynamodb.Statement) {
+	arguments, err := statement.Arguments()
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Invalid PartiQL statement")
+		return
+	}
+	if !statement.IsRead() && !canWriteItems() {
+		return
+	}
+
+	history := loadStatementHistory()
+	history.Add(cmd.UiState.Profile, cmd.UiState.SelectedItems[tableNamePlaceHolder], strings.TrimSpace(statement.Statement))
+	if err := cmd.SaveLocalState(statementHistoryStateName, history); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Unable to save the PartiQL history")
+	}
+
+	if currentNav := peekNavigation(); currentNav != nil {
+		dynamoDBState(currentNav).Statement = &statement
+	}
+
+	// Start from the configured arguments, so running again replaces the previous statement
+	command := cmd.UiState.Resource.GetCommand(cmd.UiState.Command.Name)
+	command.Arguments = append(append([]string{}, command.Arguments...), arguments...)
+	cmd.UiState.Command = command
+
+	// Reset pagination state for the new statement
+	cmd.UiState.CurrentPageToken = ""
+	cmd.UiState.PageHistory = []string{}
+
+	_, body := executeCommand(cmd.UiState.Command)
+	Body = body
+
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Form fields of the read options, shared by the query form and the page size form
+const (
+	reverseOrderField   = "__reverse"
+	consistentReadField = "__consistentRead"
+	pageSizeField       = "__pageSize"
+)
+
+// queryOptionFields returns the order, consistency and page size fields of the query form.
+// Global secondary indexes only support eventually consistent reads, they get no consistency field.
+func queryOptionFields(state dynamodb.QueryState, globalIndex bool) []ui.InputField {
+	fields := []ui.InputField{
+		{Label: "Reverse order (descending sort key)", Key: reverseOrderField, DefaultValue: strconv.FormatBool(state.Reverse), Checkbox: true},
+	}
+	if !globalIndex {
+		fields = append(fields, ui.InputField{Label: "Strongly consistent read", Key: consistentReadField, DefaultValue: strconv.FormatBool(state.ConsistentRead), Checkbox: true})
+	}
+	return append(fields, pageSizeInputField(state))
+}
+
+func pageSizeInputField(state dynamodb.QueryState) ui.InputField {
+	pageSize := state.PageSize
+	if pageSize == 0 {
+		pageSize = configuredPageSize()
+	}
+	return ui.InputField{Label: "Page size (items per request)", Key: pageSizeField, DefaultValue: strconv.Itoa(pageSize)}
+}
+
+// applyQueryOptionValues sets the read options of the state from the form values
+func applyQueryOptionValues(state *dynamodb.QueryState, values map[string]string, globalIndex bool) error {
+	pageSize, err := readPageSize(values[pageSizeField])
+	if err != nil {
+		return err
+	}
+	state.Reverse = values[reverseOrderField] == "true"
+	state.ConsistentRead = !globalIndex && values[consistentReadField] == "true"
+	state.PageSize = pageSize
+	return nil
+}
+
+// readPageSize parses the page size field, 0 stands for the limit configured for the command
+func readPageSize(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	pageSize, err := strconv.Atoi(value)
+	if err != nil || pageSize < 1 {
+		return 0, fmt.Errorf("the page size must be a positive number")
+	}
+	if pageSize == configuredPageSize() {
+		return 0, nil
+	}
+	return pageSize, nil
+}
+
+// configuredPageSize returns the --limit configured for the current command, 0 when it has none
+func configuredPageSize() int {
+	command := cmd.UiState.Resource.GetCommand(cmd.UiState.Command.Name)
+	value, _ := command.ArgumentValue("--limit")
+	pageSize, _ := strconv.Atoi(value)
+	return pageSize
+}
+
+// isGlobalIndexQuery reports whether the state queries a global secondary index of the selected table
+func isGlobalIndexQuery(state *dynamodb.QueryState) bool {
+	if state.IndexName == "" {
+		return false
+	}
+	// An unknown index type lets consistent reads through, the AWS CLI reports a global index
+	index, ok := findTableIndex(state.IndexName)
+	return ok && index.IsGlobal()
+}
+
+// handleReverseOrder queries again in the opposite sort key order, from the first page
+func handleReverseOrder(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	state := currentQueryState()
+	state.Reverse = !state.Reverse
+	if !runQueryState(state) {
+		state.Reverse = !state.Reverse
+	}
+	return nil
+}
+
+// handleConsistentRead switches between eventually and strongly consistent reads, from the first page
+func handleConsistentRead(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	state := currentQueryState()
+	if !state.ConsistentRead && isGlobalIndexQuery(state) {
+		logger.Logger.Warn().Str("index", state.IndexName).Msg("Global secondary indexes only support eventually consistent reads")
+		return nil
+	}
+	state.ConsistentRead = !state.ConsistentRead
+	if !runQueryState(state) {
+		state.ConsistentRead = !state.ConsistentRead
+	}
+	return nil
+}
+
+// handlePageSize asks how many items each request reads, then fetches the first page again
+func handlePageSize(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	state := currentQueryState()
+	previousBody := Body
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Page size: %s ", cmd.UiState.Command.Name),
+		Fields: []ui.InputField{pageSizeInputField(*state)},
+		OnValidate: func(values map[string]string) error {
+			_, err := readPageSize(values[pageSizeField])
+			return err
+		},
+		OnSubmit: func(values map[string]string) {
+			pageSize, _ := readPageSize(values[pageSizeField])
+			state.PageSize = pageSize
+			if !runQueryState(state) {
+				restore()
+			}
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	commandParser "github.com/cmd-tools/aws-commander/parser"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// savedQueriesStateName is the local state file keeping the saved queries between sessions
+const savedQueriesStateName = "saved-queries"
+
+// savedQueryIndexType is the index type of the saved query rows of the index list
+const savedQueryIndexType = "Saved Query"
+
+// saveQueryNameField is the form field of the saved query name
+const saveQueryNameField = "__name"
+
+// savedQueries is loaded from the local state on first use
+var savedQueries dynamodb.SavedQueries
+
+func loadSavedQueries() dynamodb.SavedQueries {
+	if savedQueries == nil {
+		savedQueries = dynamodb.SavedQueries{}
+		if err := cmd.LoadLocalState(savedQueriesStateName, &savedQueries); err != nil {
+			logger.Logger.Warn().Err(err).Msg("Unable to load the saved queries")
+		}
+	}
+	return savedQueries
+}
+
+// isIndexList reports whether the command lists the indexes of a DynamoDB table (describe-table)
+func isIndexList(command cmd.Command) bool {
+	return cmd.UiState.Resource.Name == "dynamodb" && command.Parse.Type == "keys"
+}
+
+// appendSavedQueryRows lists the saved queries of the table after its indexes, with the keys of the index they query
+func appendSavedQueryRows(commandParsed *commandParser.ParseCommandResult) {
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	for _, query := range loadSavedQueries().Queries(cmd.UiState.Profile, tableName) {
+		indexName := savedQueryIndexName(query)
+		index, ok := dynamodb.FindIndex(commandParsed.Indexes, indexName)
+		if !ok {
+			logger.Logger.Debug().Str("query", query.Name).Str("index", indexName).Msg("The index of the saved query no longer exists")
+			continue
+		}
+		commandParsed.Values = append(commandParsed.Values, []string{
+			query.Name,
+			savedQueryIndexType,
+			fmt.Sprintf("%d", len(index.Keys)),
+			fmt.Sprintf("%s on %s", index.KeyDetails(), indexName),
+		})
+	}
+}
+
+// savedQueryIndexName returns the index list row queried by the saved query
+func savedQueryIndexName(query dynamodb.SavedQuery) string {
+	if query.IndexName == "" {
+		return dynamodb.PrimaryIndexName
+	}
+	return query.IndexName
+}
+
+// loadSavedQueryState fills the query state of the current level from the named saved query, returning the index it queries
+func loadSavedQueryState(name string) (string, bool) {
+	query, ok := loadSavedQueries().Find(cmd.UiState.Profile, cmd.UiState.SelectedItems[tableNamePlaceHolder], name)
+	if !ok {
+		return "", false
+	}
+	*currentQueryState() = query.State()
+	return savedQueryIndexName(query), true
+}
+
+// handleSaveQuery saves the current query under a name, offering the name of the saved query it was loaded from
+func handleSaveQuery(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isDynamoDBItemsCommand() {
+		return event
+	}
+
+	state := currentQueryState()
+	if state.KeyCondition.Expression == "" {
+		return event
+	}
+
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	name := ""
+	if selectedName := getSelectedIndexName(); selectedName != "" {
+		if _, ok := loadSavedQueries().Find(cmd.UiState.Profile, tableName, selectedName); ok {
+			name = selectedName
+		}
+	}
+
+	previousBody := Body
+	restore := func() {
+		Body = previousBody
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title:  fmt.Sprintf(" Save query on %s ", tableName),
+		Fields: []ui.InputField{{Label: "Name", Key: saveQueryNameField, DefaultValue: name}},
+		OnValidate: func(values map[string]string) error {
+			return validateSavedQueryName(strings.TrimSpace(values[saveQueryNameField]))
+		},
+		OnSubmit: func(values map[string]string) {
+			query := dynamodb.NewSavedQuery(values[saveQueryNameField], *state)
+			saved := loadSavedQueries()
+			if err := saved.Save(cmd.UiState.Profile, tableName, query); err != nil {
+				logger.Logger.Warn().Err(err).Msg("Unable to save the query")
+				return
+			}
+			if err := cmd.SaveLocalState(savedQueriesStateName, saved); err != nil {
+				logger.Logger.Error().Err(err).Msg("Unable to save the saved queries")
+			} else {
+				logger.Logger.Info().Str("query", query.Name).Str("table", tableName).Msg("Query saved")
+			}
+
+			// The index list shows the saved queries, it is rendered again when going back to it
+			for index := range cmd.UiState.NavigationStack {
+				nav := &cmd.UiState.NavigationStack[index]
+				if nav.Type == cmd.BreadcrumbDependentCmd && nav.Value == "describe-table" {
+					nav.NeedsRender = true
+				}
+			}
+			restore()
+		},
+		OnCancel: restore,
+		App:      App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+	return nil
+}
+
+// validateSavedQueryName rejects names of the table indexes, the index list could not tell them apart
+func validateSavedQueryName(name string) error {
+	if name == "" {
+		return fmt.Errorf("set a name for the query")
+	}
+	if name == dynamodb.PrimaryIndexName {
+		return fmt.Errorf("%s is the name of the table keys", name)
+	}
+	table, err := describeCurrentTable()
+	if err != nil {
+		return nil
+	}
+	for _, index := range slices.Concat(table.GlobalSecondaryIndexes, table.LocalSecondaryIndexes) {
+		if index.IndexName == name {
+			return fmt.Errorf("%s is the name of an index", name)
+		}
+	}
+	return nil
+}
+
+// handleDeleteSavedQuery deletes the saved query selected in the index list after a confirmation
+func handleDeleteSavedQuery(event *tcell.EventKey) *tcell.EventKey {
+	table, ok := Body.(*tview.Table)
+	if !ok || App.GetFocus() != Body || isBackgroundTaskRunning() || !isDescribeTableCommand() {
+		return event
+	}
+	row, _ := table.GetSelection()
+	if row <= 0 || table.GetCell(row, 1).Text != savedQueryIndexType {
+		return event
+	}
+
+	name := table.GetCell(row, 0).Text
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	restore := func(*tview.Flex) {
+		Body = table
+		updateRootView(nil)
+		App.SetFocus(Body)
+	}
+
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: fmt.Sprintf("Delete the saved query %s of %s?", name, tableName),
+		LeftChoice: ui.ModalChoice{
+			Name: "Delete",
+			Handler: func(flex *tview.Flex) {
+				saved := loadSavedQueries()
+				saved.Delete(cmd.UiState.Profile, tableName, name)
+				if err := cmd.SaveLocalState(savedQueriesStateName, saved); err != nil {
+					logger.Logger.Error().Err(err).Msg("Unable to save the saved queries")
+				}
+
+				Body = table
+				if !rerenderCurrentResult() {
+					restore(flex)
+					return
+				}
+				App.SetFocus(Body)
+			},
+		},
+		RightChoice: ui.ModalChoice{Name: "Cancel", Handler: restore},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+	return nil
+}
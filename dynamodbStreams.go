@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
+	"github.com/cmd-tools/aws-commander/logger"
+	commandParser "github.com/cmd-tools/aws-commander/parser"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Form fields of the stream form
+const (
+	streamShardField          = "__shard"
+	streamIteratorTypeField   = "__iteratorType"
+	streamSequenceNumberField = "__sequenceNumber"
+)
+
+// isStreamRecords reports whether the command reads the records of a DynamoDB stream (get-records)
+func isStreamRecords(command cmd.Command) bool {
+	return cmd.UiState.Resource.Name == "dynamodb" && command.Service == dynamodb.StreamsService && command.Parse.AttributeName == "Records"
+}
+
+// isStreamRecordsCommand reports whether the current view shows the records of a DynamoDB stream
+func isStreamRecordsCommand() bool {
+	currentNav := peekNavigation()
+	return cmd.UiState.Command.RequiresStreamInput && currentNav != nil && currentNav.Value == cmd.UiState.Command.Name &&
+		(currentNav.Type == cmd.BreadcrumbCommand || currentNav.Type == cmd.BreadcrumbDependentCmd)
+}
+
+// renderStreamRecords shows the stream records with their keys and changed attributes, the images and diff are in the JSON viewer
+func renderStreamRecords(command *cmd.Command, commandOutput string) string {
+	view, err := dynamodb.StreamRecordsView(commandOutput)
+	if err != nil {
+		// e.g. an expired iterator, the parser shows the output as is
+		logger.Logger.Warn().Err(err).Msg(fmt.Sprintf("Unable to read the stream records: %s", strings.Join(strings.Fields(commandOutput), " ")))
+		return commandOutput
+	}
+	command.Parse.Columns = dynamodb.StreamRecordColumns
+	return view
+}
+
+// streamSummary describes the shard and iterator of the records shown at the current level
+func streamSummary(commandParsed *commandParser.ParseCommandResult) {
+	currentNav := peekNavigation()
+	if currentNav == nil || dynamoDBState(currentNav).Stream == nil {
+		return
+	}
+	stream := dynamoDBState(currentNav).Stream
+	commandParsed.Summary = fmt.Sprintf("%s from %s", stream.ShardId, stream.IteratorType)
+}
+
+// handleChooseShard opens the stream form again, from the shard and iterator of the current records
+func handleChooseShard(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() || !isStreamRecordsCommand() {
+		return event
+	}
+	showStreamForm()
+	return nil
+}
+
+// showStreamForm lists the shards of the stream of the selected table, to choose where the records are read from
+func showStreamForm() {
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+
+	tableName := cmd.UiState.SelectedItems[tableNamePlaceHolder]
+	table, err := describeCurrentTable()
+	if err != nil {
+		showStreamError(fmt.Sprintf("Unable to describe %s: %s", tableName, strings.Join(strings.Fields(err.Error()), " ")))
+		return
+	}
+	if table.StreamSpecification == nil || !table.StreamSpecification.StreamEnabled || table.LatestStreamArn == "" {
+		showStreamError(fmt.Sprintf("Streams are not enabled on %s.", tableName))
+		return
+	}
+
+	stream, err := dynamodb.DescribeStream(func(startShardId string) (string, error) {
+		arguments := []string{"--stream-arn", table.LatestStreamArn}
+		if startShardId != "" {
+			arguments = append(arguments, "--exclusive-start-shard-id", startShardId)
+		}
+		return runStreamCommand("describe-stream", arguments...)
+	})
+	if err != nil {
+		showStreamError(fmt.Sprintf("Unable to describe the stream of %s: %s", tableName, strings.Join(strings.Fields(err.Error()), " ")))
+		return
+	}
+	if len(stream.Shards) == 0 {
+		showStreamError(fmt.Sprintf("The stream of %s has no shards yet.", tableName))
+		return
+	}
+
+	// Start from the records read at this level, or from the oldest record of the newest open shard
+	position := dynamodb.StreamPosition{ShardId: stream.Shards[len(stream.Shards)-1].ShardId, IteratorType: dynamodb.IteratorTrimHorizon}
+	for _, shard := range stream.Shards {
+		if shard.IsOpen() {
+			position.ShardId = shard.ShardId
+		}
+	}
+	if currentNav := peekNavigation(); currentNav != nil && dynamoDBState(currentNav).Stream != nil {
+		position = *dynamoDBState(currentNav).Stream
+	}
+
+	showStreamFormWith(stream, position, "")
+}
+
+// showStreamFormWith displays the stream form with the given position, and the error of a previous attempt if any
+func showStreamFormWith(stream dynamodb.StreamDescription, position dynamodb.StreamPosition, formError string) {
+	// Shards are shown with their state, the form maps them back to the shard ids
+	shardIds := make(map[string]string)
+	var shardOptions []string
+	selectedShard := ""
+	for _, shard := range stream.Shards {
+		shardIds[shard.Label()] = shard.ShardId
+		shardOptions = append(shardOptions, shard.Label())
+		if shard.ShardId == position.ShardId {
+			selectedShard = shard.Label()
+		}
+	}
+
+	readPosition := func(values map[string]string) dynamodb.StreamPosition {
+		return dynamodb.StreamPosition{
+			ShardId:        shardIds[values[streamShardField]],
+			IteratorType:   values[streamIteratorTypeField],
+			SequenceNumber: strings.TrimSpace(values[streamSequenceNumberField]),
+		}
+	}
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Stream of %s (%s) ", stream.TableName, stream.StreamViewType),
+		Fields: []ui.InputField{
+			{Label: fmt.Sprintf("Shard (%d)", len(stream.Shards)), Key: streamShardField, DefaultValue: selectedShard, Options: shardOptions},
+			{Label: "Iterator type", Key: streamIteratorTypeField, DefaultValue: position.IteratorType, Options: dynamodb.IteratorTypes},
+			{Label: "Sequence number (AT/AFTER_SEQUENCE_NUMBER only)", Key: streamSequenceNumberField, DefaultValue: position.SequenceNumber},
+		},
+		OnValidate: func(values map[string]string) error {
+			_, err := readPosition(values).ShardIteratorArguments(stream.StreamArn)
+			return err
+		},
+		InitialError: formError,
+		OnSubmit: func(values map[string]string) {
+			position := readPosition(values)
+			arguments, _ := position.ShardIteratorArguments(stream.StreamArn)
+			output, err := runStreamCommand("get-shard-iterator", arguments...)
+			var iterator string
+			if err == nil {
+				iterator, err = dynamodb.ParseShardIterator(output)
+			}
+			if err != nil {
+				showStreamFormWith(stream, position, strings.Join(strings.Fields(err.Error()), " "))
+				return
+			}
+			readStreamRecords(position, iterator)
+		},
+		OnCancel: func() {
+			// Without records yet, cancelling leaves the command
+			if currentNav := peekNavigation(); currentNav != nil && currentNav.CachedBody != nil {
+				Body = currentNav.CachedBody
+			} else {
+				handleDependentCommandBack()
+			}
+			updateRootView(nil)
+			App.SetFocus(Body)
+		},
+		App: App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+}
+
+// readStreamRecords runs get-records from the shard iterator, the next pages follow NextShardIterator
+func readStreamRecords(position dynamodb.StreamPosition, iterator string) {
+	if currentNav := peekNavigation(); currentNav != nil {
+		dynamoDBState(currentNav).Stream = &position
+	}
+
+	cmd.UiState.Command = cmd.UiState.Resource.GetCommand(cmd.UiState.Command.Name)
+
+	// The iterator is the token of the first page
+	cmd.UiState.CurrentPageToken = iterator
+	cmd.UiState.PageHistory = []string{}
+
+	_, body := executeCommand(cmd.UiState.Command)
+	Body = body
+
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
+
+// runStreamCommand runs a DynamoDB Streams command with the defaults of the resource
+func runStreamCommand(name string, arguments ...string) (string, error) {
+	command := cmd.UiState.Resource.NewCommand(name, arguments...)
+	command.Service = dynamodb.StreamsService
+	return command.Execute(context.Background(), cmd.UiState.Resource.Name, cmd.UiState.Profile)
+}
+
+// showStreamError explains why the stream can not be read, OK leaves the command
+func showStreamError(message string) {
+	modal := ui.CreateModal(ui.ModalProperties{
+		Title: message,
+		LeftChoice: ui.ModalChoice{
+			Name: "OK",
+			Handler: func(*tview.Flex) {
+				if currentNav := peekNavigation(); currentNav != nil && currentNav.CachedBody != nil {
+					Body = currentNav.CachedBody
+				} else {
+					handleDependentCommandBack()
+				}
+				updateRootView(nil)
+				App.SetFocus(Body)
+			},
+		},
+	}, nil)
+
+	Body = modal
+	updateRootView(nil)
+	App.SetFocus(modal)
+}
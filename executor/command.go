@@ -1,8 +1,10 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/cmd-tools/aws-commander/logger"
 )
@@ -28,3 +30,37 @@ func ExecCommand(command string, args []string) string {
 
 	return string(out)
 }
+
+// ExecCommandContext runs a command that is killed as soon as ctx is cancelled
+func ExecCommandContext(ctx context.Context, command string, args []string) (string, error) {
+
+	out, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return string(out), ctx.Err()
+		}
+		logger.Logger.Err(err).Msg(fmt.Sprintf("Failed to run ExecCommandContext: %s", out))
+	}
+
+	return string(out), nil
+}
+
+// ExecCommandWithError runs a command killed when ctx is cancelled, failing with its output when it exits with an error
+func ExecCommandWithError(ctx context.Context, command string, args []string) (string, error) {
+
+	out, err := exec.CommandContext(ctx, command, args...).CombinedOutput()
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return string(out), ctx.Err()
+		}
+		logger.Logger.Err(err).Msg(fmt.Sprintf("Failed to run ExecCommandWithError: %s", out))
+		if message := strings.TrimSpace(string(out)); message != "" {
+			return string(out), fmt.Errorf("%s", message)
+		}
+		return string(out), err
+	}
+
+	return string(out), nil
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
 	"github.com/cmd-tools/aws-commander/constants"
 	"github.com/cmd-tools/aws-commander/logger"
 	commandParser "github.com/cmd-tools/aws-commander/parser"
@@ -36,10 +38,7 @@ func executeCommand(command cmd.Command) (string, tview.Primitive) {
 		}
 	}
 
-	commandParsed := commandParser.ParseCommand(command, commandOutput)
-	body := commandParser.ParseToObject(command.View, commandParsed, command, itemHandler, App, func() {
-		updateRootView(nil)
-	}, func() *tview.Flex { return createHeader(nil) }, createFooter, LogView, IsLogViewEnabled)
+	_, body := renderCommandOutput(command, commandOutput)
 
 	// Cache the result if rerunOnBack is false
 	if !command.RerunOnBack {
@@ -49,6 +48,71 @@ func executeCommand(command cmd.Command) (string, tview.Primitive) {
 	return commandOutput, body
 }
 
+// renderCommandOutput parses a command output into its view and keeps the result rows in the navigation state
+func renderCommandOutput(command cmd.Command, commandOutput string) (commandParser.ParseCommandResult, tview.Primitive) {
+	applyDynamoDBColumns(&command)
+	if isStreamRecords(command) {
+		commandOutput = renderStreamRecords(&command, commandOutput)
+	}
+	if isBackupList(command) {
+		command.Parse.Columns = dynamodb.BackupColumns
+	}
+
+	commandParsed := commandParser.ParseCommand(command, commandOutput)
+	if isIndexList(command) {
+		if currentNav := peekNavigation(); currentNav != nil {
+			dynamoDBState(currentNav).Indexes = commandParsed.Indexes
+		}
+		appendSavedQueryRows(&commandParsed)
+	}
+	if isStreamRecords(command) {
+		streamSummary(&commandParsed)
+	}
+	if isBackupList(command) {
+		backupSummary(&commandParsed)
+	}
+	updateNavigationRowData(commandParsed.RawData)
+	if currentNav := peekNavigation(); currentNav != nil {
+		// Show the order, consistency and page size chosen for the scan or query next to the counts
+		if query := dynamoDBState(currentNav).Query; query != nil && isDynamoDBItems(command) {
+			if options := query.ReadOptions(); options != "" {
+				commandParsed.Summary = strings.TrimPrefix(fmt.Sprintf("%s, %s", commandParsed.Summary, options), ", ")
+			}
+		}
+		currentNav.RenderedAsDynamoDBJson = cmd.UiState.ShowDynamoDBJsonFormat
+		currentNav.NeedsRender = false
+	}
+	body := commandParser.ParseToObject(command.View, commandParsed, command, itemHandler, App, func() {
+		updateRootView(nil)
+	}, func() *tview.Flex { return createHeader(nil) }, createFooter, LogView, IsLogViewEnabled)
+
+	return commandParsed, body
+}
+
+// rerenderCurrentResult renders the cached output of the current level again, e.g. after a display setting changed
+func rerenderCurrentResult() bool {
+	currentNav := peekNavigation()
+	if currentNav == nil || currentNav.CachedResult == "" {
+		return false
+	}
+
+	// Keep the selected row of the previous rendering
+	selectedRow := -1
+	if table, ok := currentNav.CachedBody.(*tview.Table); ok {
+		selectedRow, _ = table.GetSelection()
+	}
+
+	_, body := renderCommandOutput(cmd.UiState.Command, currentNav.CachedResult)
+	if table, ok := body.(*tview.Table); ok && selectedRow > 0 && selectedRow < table.GetRowCount() {
+		table.Select(selectedRow, 0)
+	}
+
+	updateNavigationCache(currentNav.CachedResult, body)
+	Body = body
+	updateRootView(nil)
+	return true
+}
+
 // executeDependentCommand handles execution of dependent commands
 func executeDependentCommand(selectedCommandName string) {
 	cmd.UiState.Command = cmd.UiState.Resource.GetCommand(selectedCommandName)
@@ -65,6 +129,18 @@ func executeDependentCommand(selectedCommandName string) {
 		return
 	}
 
+	// Check if command requires a statement (e.g., DynamoDB PartiQL)
+	if cmd.UiState.Command.RequiresStatementInput {
+		showStatementForm()
+		return
+	}
+
+	// Check if command requires a stream shard and iterator (e.g., DynamoDB Streams)
+	if cmd.UiState.Command.RequiresStreamInput {
+		showStreamForm()
+		return
+	}
+
 	cmd.UiState.CommandBarVisible = false
 	Search.SetText("")
 	cmd.UiState.OriginalTableData = nil
@@ -96,18 +172,18 @@ func createExecuteCommandView(selectedCommandName string) {
 
 // itemHandler handles item selection from command results
 func itemHandler(selectedItemName string) {
+	selectItem(selectedItemName, selectedItemName)
+}
+
+// selectItem stores the selected value under the command resource placeholder and runs its dependent commands
+func selectItem(breadcrumbLabel string, selectedValue string) {
 	resourceName := cmd.VariablePlaceHolderPrefix + strings.ToUpper(cmd.UiState.Command.ResourceName)
-	cmd.UiState.SelectedItems[resourceName] = selectedItemName
+	cmd.UiState.SelectedItems[resourceName] = selectedValue
 
 	AutoCompletionWordList = append(cmd.UiState.Resource.GetCommandNames(), constants.Profiles)
 
 	// Find all commands that depend on the current command
-	var dependentCommands []cmd.Command
-	for _, c := range cmd.UiState.Resource.Commands {
-		if c.DependsOn == cmd.UiState.Command.Name {
-			dependentCommands = append(dependentCommands, c)
-		}
-	}
+	dependentCommands := getDependentCommands(cmd.UiState.Command.Name)
 
 	if len(dependentCommands) == 0 {
 		// No dependent command found, show command list
@@ -117,7 +193,7 @@ func itemHandler(selectedItemName string) {
 		Body = createCommandView(cmd.UiState.Resource.GetCommandNames())
 	} else if len(dependentCommands) == 1 {
 		// Only one dependent command, execute it directly
-		pushNavigation(cmd.BreadcrumbSelectedItem, selectedItemName)
+		pushNavigation(cmd.BreadcrumbSelectedItem, breadcrumbLabel)
 
 		cmd.UiState.Command = dependentCommands[0]
 		pushNavigation(cmd.BreadcrumbDependentCmd, cmd.UiState.Command.Name)
@@ -131,6 +207,18 @@ func itemHandler(selectedItemName string) {
 			return
 		}
 
+		// Check if command requires a statement (e.g., DynamoDB PartiQL)
+		if cmd.UiState.Command.RequiresStatementInput {
+			showStatementForm()
+			return
+		}
+
+		// Check if command requires a stream shard and iterator (e.g., DynamoDB Streams)
+		if cmd.UiState.Command.RequiresStreamInput {
+			showStreamForm()
+			return
+		}
+
 		cmd.UiState.CommandBarVisible = false
 		Search.SetText("")
 		cmd.UiState.OriginalTableData = nil
@@ -138,7 +226,7 @@ func itemHandler(selectedItemName string) {
 		Body = body
 	} else {
 		// Multiple dependent commands, show selection list
-		pushNavigation(cmd.BreadcrumbSelectedItem, selectedItemName)
+		pushNavigation(cmd.BreadcrumbSelectedItem, breadcrumbLabel)
 
 		var commandNames []string
 		for _, c := range dependentCommands {
@@ -155,6 +243,65 @@ func itemHandler(selectedItemName string) {
 	updateRootView(nil)
 }
 
+// getDependentCommands returns the commands of the current resource that depend on the given command
+func getDependentCommands(commandName string) []cmd.Command {
+	var dependentCommands []cmd.Command
+	for _, c := range cmd.UiState.Resource.Commands {
+		if c.DependsOn == commandName {
+			dependentCommands = append(dependentCommands, c)
+		}
+	}
+	return dependentCommands
+}
+
+// getSelectedRowData returns the selected row number and its raw JSON for tables showing JSON rows.
+// The row number is the one of the unfiltered table, also when a search filter moved the row up.
+func getSelectedRowData() (int, interface{}, bool) {
+	table, ok := Body.(*tview.Table)
+	if !ok {
+		return 0, nil, false
+	}
+
+	currentNav := peekNavigation()
+	if currentNav == nil {
+		return 0, nil, false
+	}
+
+	row, _ := table.GetSelection()
+	row = cmd.UiState.OriginalRow(row)
+	if row <= 0 || row > len(currentNav.RowData) {
+		return 0, nil, false
+	}
+	return row, currentNav.RowData[row-1], true
+}
+
+// handleRunOnRow runs the dependent commands of the current command with the selected row as item.
+// The row JSON is available to arguments as $<RESOURCENAME>, and its fields as $<RESOURCENAME>.Field
+func handleRunOnRow(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	row, rowData, ok := getSelectedRowData()
+	if !ok {
+		return event
+	}
+
+	if cmd.UiState.Command.ResourceName == "" || len(getDependentCommands(cmd.UiState.Command.Name)) == 0 {
+		logger.Logger.Warn().Str("command", cmd.UiState.Command.Name).Msg("No dependent commands to run on the selected row")
+		return nil
+	}
+
+	rowJson, err := json.Marshal(rowData)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to marshal selected row")
+		return nil
+	}
+
+	selectItem(fmt.Sprintf("Row %d", row), string(rowJson))
+	return nil
+}
+
 // defaultKeyCombinations defines the default keyboard shortcuts
 func defaultKeyCombinations() []ui.CustomShortCut {
 	shortcuts := []ui.CustomShortCut{
@@ -185,6 +332,31 @@ func defaultKeyCombinations() []ui.CustomShortCut {
 			Description: "Previous Page",
 			Handle:      handlePreviousPage,
 		},
+		{
+			Rune:        'x',
+			Description: "Run On Row",
+			Handle:      handleRunOnRow,
+		},
+		{
+			Rune:        'm',
+			Description: "Mark For Compare",
+			Handle:      handleMarkForCompare,
+		},
+		{
+			Rune:        'd',
+			Description: "Compare With Marked",
+			Handle:      handleCompareWithMarked,
+		},
+		{
+			Rune:        'a',
+			Description: "Fetch All Pages",
+			Handle:      handleFetchAllPages,
+		},
+		{
+			Rune:        'N',
+			Description: "Fetch N Pages",
+			Handle:      handleFetchNPages,
+		},
 		{
 			Rune:        'y',
 			Description: "Copy (Yank)",
@@ -202,8 +374,155 @@ func defaultKeyCombinations() []ui.CustomShortCut {
 		},
 	}
 
-	// Add 'v' shortcut only when viewing DynamoDB items in JSON viewer
-	if cmd.UiState.InDynamoDBJsonViewer {
+	// Add the item shortcuts only when viewing the items of a DynamoDB scan or query, writes only for writable profiles
+	if isDynamoDBItemsCommand() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        'f',
+			Description: "Filter Items",
+			Handle:      handleFilterItems,
+		}, ui.CustomShortCut{
+			Rune:        'c',
+			Description: "Choose Columns",
+			Handle:      handleChooseColumns,
+		}, ui.CustomShortCut{
+			Rune:        'v',
+			Description: "Toggle JSON Format",
+			Handle:      handleToggleItemsFormat,
+		}, ui.CustomShortCut{
+			Rune:        'E',
+			Description: "Export Table",
+			Handle:      handleExportTable,
+		}, ui.CustomShortCut{
+			Rune:        'o',
+			Description: "Table Overview",
+			Handle:      handleTableOverview,
+		}, ui.CustomShortCut{
+			Rune:        'K',
+			Description: "Compare Tables",
+			Handle:      handleCompareTables,
+		}, ui.CustomShortCut{
+			Rune:        'C',
+			Description: "Toggle Consistent Read",
+			Handle:      handleConsistentRead,
+		}, ui.CustomShortCut{
+			Rune:        'l',
+			Description: "Page Size",
+			Handle:      handlePageSize,
+		}, ui.CustomShortCut{
+			Rune:        '#',
+			Description: "Count Items",
+			Handle:      handleCountItems,
+		})
+		if cmd.UiState.Command.Name == "query" {
+			shortcuts = append(shortcuts, ui.CustomShortCut{
+				Rune:        'r',
+				Description: "Reverse Order",
+				Handle:      handleReverseOrder,
+			}, ui.CustomShortCut{
+				Rune:        'S',
+				Description: "Save Query",
+				Handle:      handleSaveQuery,
+			})
+		}
+		if !cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+			shortcuts = append(shortcuts, ui.CustomShortCut{
+				Rune:        'i',
+				Description: "New Item",
+				Handle:      handleNewItem,
+			}, ui.CustomShortCut{
+				Rune:        'D',
+				Description: "Delete Item",
+				Handle:      handleDeleteItem,
+			}, ui.CustomShortCut{
+				Rune:        'I',
+				Description: "Import Items",
+				Handle:      handleImportItems,
+			})
+		}
+	} else if isDynamoDBTableList() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        'E',
+			Description: "Export Table",
+			Handle:      handleExportTable,
+		}, ui.CustomShortCut{
+			Rune:        'o',
+			Description: "Table Overview",
+			Handle:      handleTableOverview,
+		}, ui.CustomShortCut{
+			Rune:        'K',
+			Description: "Compare Tables",
+			Handle:      handleCompareTables,
+		})
+		if !cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+			shortcuts = append(shortcuts, ui.CustomShortCut{
+				Rune:        'I',
+				Description: "Import Items",
+				Handle:      handleImportItems,
+			}, ui.CustomShortCut{
+				Rune:        'B',
+				Description: "Create Backup",
+				Handle:      handleCreateBackup,
+			})
+		}
+	} else if isBackupListCommand() {
+		if !cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+			shortcuts = append(shortcuts, ui.CustomShortCut{
+				Rune:        'B',
+				Description: "Create Backup",
+				Handle:      handleCreateBackup,
+			}, ui.CustomShortCut{
+				Rune:        'R',
+				Description: "Restore Backup",
+				Handle:      handleRestoreBackup,
+			}, ui.CustomShortCut{
+				Rune:        'T',
+				Description: "Restore To Point In Time",
+				Handle:      handleRestorePointInTime,
+			})
+		}
+	} else if isDescribeTableCommand() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        'o',
+			Description: "Table Overview",
+			Handle:      handleTableOverview,
+		}, ui.CustomShortCut{
+			Rune:        'K',
+			Description: "Compare Tables",
+			Handle:      handleCompareTables,
+		}, ui.CustomShortCut{
+			Rune:        'D',
+			Description: "Delete Saved Query",
+			Handle:      handleDeleteSavedQuery,
+		})
+	} else if isStreamRecordsCommand() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        's',
+			Description: "Choose Shard",
+			Handle:      handleChooseShard,
+		})
+	} else if isJsonDiffView() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        'v',
+			Description: "Toggle JSON Format",
+			Handle: func(event *tcell.EventKey) *tcell.EventKey {
+				// Handled in the diff viewer component
+				return event
+			},
+		})
+	} else if isTableCompareReport() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        'E',
+			Description: "Export Report",
+			Handle:      handleExportCompareReport,
+		})
+	} else if isPartiqlCommand() {
+		shortcuts = append(shortcuts, ui.CustomShortCut{
+			Rune:        's',
+			Description: "Edit Statement",
+			Handle:      handleEditStatement,
+		})
+	} else if cmd.UiState.InDynamoDBJsonViewer {
+		// Add 'v' shortcut when viewing DynamoDB items in JSON viewer
 		shortcuts = append(shortcuts, ui.CustomShortCut{
 			Rune:        'v',
 			Description: "Toggle JSON Format",
@@ -212,17 +531,44 @@ func defaultKeyCombinations() []ui.CustomShortCut {
 				return event
 			},
 		})
+		if _, _, ok := editedItemParent(); ok && !cmd.IsReadOnlyProfile(cmd.UiState.Profile) {
+			shortcuts = append(shortcuts, ui.CustomShortCut{
+				Rune:        'e',
+				Description: "Edit Item",
+				Handle:      handleEditItem,
+			})
+		}
 	}
 
 	return shortcuts
 }
 
+// handleToggleItemsFormat switches the DynamoDB items table between plain values and DynamoDB JSON
+func handleToggleItemsFormat(event *tcell.EventKey) *tcell.EventKey {
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	cmd.UiState.ShowDynamoDBJsonFormat = !cmd.UiState.ShowDynamoDBJsonFormat
+	if !rerenderCurrentResult() {
+		_, body := executeCommand(cmd.UiState.Command)
+		Body = body
+		updateRootView(nil)
+	}
+	return nil
+}
+
 // handleEscKey processes ESC key navigation
 func handleEscKey(event *tcell.EventKey) *tcell.EventKey {
 	if Search.HasFocus() {
 		return event
 	}
 
+	// ESC first cancels a running background task (e.g. fetch-all pages)
+	if cancelBackgroundTask() {
+		return nil
+	}
+
 	currentState := peekNavigation()
 	if currentState == nil {
 		return nil
@@ -267,6 +613,9 @@ func handleEscKey(event *tcell.EventKey) *tcell.EventKey {
 
 	case cmd.BreadcrumbSelectedItem:
 		handleSelectedItemBack()
+
+	case cmd.BreadcrumbInfoView:
+		handleInfoViewBack()
 	}
 
 	updateRootView(nil)
@@ -333,6 +682,12 @@ func handleJsonViewBack() {
 	cmd.UiState.OriginalTableData = nil
 
 	currentCmdState := peekNavigation()
+	if currentCmdState != nil && isDynamoDBItemsCommand() &&
+		(currentCmdState.NeedsRender || currentCmdState.RenderedAsDynamoDBJson != cmd.UiState.ShowDynamoDBJsonFormat) &&
+		rerenderCurrentResult() {
+		// The format was toggled or the item edited in the viewer, the items table follows it
+		return
+	}
 	if currentCmdState != nil && currentCmdState.CachedBody != nil {
 		Body = currentCmdState.CachedBody
 		logger.Logger.Debug().Msg(fmt.Sprintf("[ESC] Using cached result for command: %s", cmd.UiState.Command.Name))
@@ -342,6 +697,23 @@ func handleJsonViewBack() {
 	}
 }
 
+// handleInfoViewBack navigates back from an info view to the view it was opened from
+func handleInfoViewBack() {
+	popNavigation()
+
+	cmd.UiState.CommandBarVisible = false
+	Search.SetText("")
+	cmd.UiState.OriginalTableData = nil
+
+	currentCmdState := peekNavigation()
+	if currentCmdState != nil && currentCmdState.CachedBody != nil {
+		Body = currentCmdState.CachedBody
+	} else {
+		_, body := executeCommand(cmd.UiState.Command)
+		Body = body
+	}
+}
+
 // handleDependentCommandBack navigates back from a dependent command
 func handleDependentCommandBack() {
 	popNavigation()
@@ -363,7 +735,10 @@ func handleDependentCommandBack() {
 		parentCommandName := parentState.Value
 		cmd.UiState.Command = cmd.UiState.Resource.GetCommand(parentCommandName)
 
-		if parentState.CachedBody != nil && !cmd.UiState.Command.RerunOnBack {
+		if parentState.NeedsRender && rerenderCurrentResult() {
+			// e.g. a query was saved, the index list shows it
+			return
+		} else if parentState.CachedBody != nil && !cmd.UiState.Command.RerunOnBack {
 			Body = parentState.CachedBody
 			logger.Logger.Debug().Msg(fmt.Sprintf("[ESC] Using cached result for parent command: %s", parentCommandName))
 		} else {
@@ -413,8 +788,8 @@ func handleSelectedItemBack() {
 
 // handleNextPage handles pagination to next page
 func handleNextPage(event *tcell.EventKey) *tcell.EventKey {
-	// Don't handle if an input form has focus
-	if App.GetFocus() != Body {
+	// Don't handle if an input form has focus or a background task owns the body
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
 		return event
 	}
 
@@ -458,8 +833,8 @@ func handleNextPage(event *tcell.EventKey) *tcell.EventKey {
 
 // handlePreviousPage handles pagination to previous page
 func handlePreviousPage(event *tcell.EventKey) *tcell.EventKey {
-	// Don't handle if an input form has focus
-	if App.GetFocus() != Body {
+	// Don't handle if an input form has focus or a background task owns the body
+	if App.GetFocus() != Body || isBackgroundTaskRunning() {
 		return event
 	}
 
@@ -3,11 +3,15 @@ package main
 import (
 	"bytes"
 	"flag"
+	"fmt"
+	"os"
 
 	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/genconfig"
 	"github.com/cmd-tools/aws-commander/cmd/profile"
 	"github.com/cmd-tools/aws-commander/logger"
 	"github.com/rivo/tview"
+	"gopkg.in/yaml.v2"
 )
 
 // Global application state
@@ -27,11 +31,20 @@ func main() {
 	flag.Parse()
 
 	logger.InitLog(IsLogViewEnabled)
+
+	if flag.Arg(0) == "gen-config" {
+		os.Exit(runGenConfig(flag.Args()[1:]))
+	}
+
 	logger.Logger.Info().Msg("Starting aws-commander")
 	logger.Logger.Debug().Msg("Loading configurations")
 
 	cmd.Init()
 
+	if flag.Arg(0) == "validate" {
+		os.Exit(runValidate())
+	}
+
 	App = tview.NewApplication()
 	Search = createSearchBar()
 	ProfileList = profile.GetList()
@@ -48,6 +61,76 @@ func main() {
 	}
 }
 
+// runValidate prints the loaded configurations with their expanded arguments and reports problems
+func runValidate() int {
+	cmd.DescribeResources(os.Stdout)
+
+	errs := cmd.Validate()
+	if len(errs) == 0 {
+		fmt.Println("\nConfiguration is valid")
+		return 0
+	}
+
+	fmt.Printf("\nFound %d configuration problems:\n", len(errs))
+	for _, err := range errs {
+		fmt.Printf("  - %v\n", err)
+	}
+	return 1
+}
+
+// runGenConfig writes a resource configuration generated from the botocore model of a service
+func runGenConfig(args []string) int {
+	flags := flag.NewFlagSet("gen-config", flag.ContinueOnError)
+	modelsDir := flags.String("models-dir", "", "Directory holding the botocore service models (default: detected from the AWS CLI install).")
+	output := flags.String("o", "", "File to write the configuration to (default: stdout).")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aws-commander gen-config [-models-dir DIR] [-o FILE] <service>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	service := flags.Arg(0)
+
+	modelsDirs := genconfig.ModelsDirCandidates()
+	if *modelsDir != "" {
+		modelsDirs = []string{*modelsDir}
+	}
+
+	serviceDir, err := genconfig.FindServiceDir(modelsDirs, service)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	model, paginators, err := genconfig.LoadServiceModel(serviceDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to load model from %s: %v\n", serviceDir, err)
+		return 1
+	}
+
+	resource, skipped := genconfig.Generate(service, model, paginators)
+	content, err := yaml.Marshal(resource)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to marshal configuration: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		fmt.Print(string(content))
+	} else if err := os.WriteFile(*output, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write %s: %v\n", *output, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Generated %d commands for %s from %s\n", len(resource.Commands), service, serviceDir)
+	for _, reason := range skipped {
+		fmt.Fprintf(os.Stderr, "  skipped %s\n", reason)
+	}
+	return 0
+}
+
 // startLogViewListener monitors the log channel and updates the log view
 func startLogViewListener() {
 	for {
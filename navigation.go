@@ -30,6 +30,13 @@ func updateNavigationCache(cachedResult string, cachedBody tview.Primitive) {
 	}
 }
 
+// updateNavigationRowData stores the raw JSON of the result rows shown at the current navigation state
+func updateNavigationRowData(rowData []interface{}) {
+	if currentNav := peekNavigation(); currentNav != nil {
+		currentNav.RowData = rowData
+	}
+}
+
 // popNavigation removes the last navigation state from the stack
 func popNavigation() *cmd.NavigationState {
 	if len(cmd.UiState.NavigationStack) == 0 {
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/logger"
+	"github.com/cmd-tools/aws-commander/ui"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// handleFetchAllPages follows the next token of the current command up to the configured limits
+func handleFetchAllPages(event *tcell.EventKey) *tcell.EventKey {
+	if _, isTable := Body.(*tview.Table); !isTable || App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	if !cmd.UiState.Command.SupportsFetchAll() {
+		logger.Logger.Warn().Str("command", cmd.UiState.Command.Name).Msg("Fetch all is only available for token based pagination")
+		return nil
+	}
+
+	maxPages, maxItems := cmd.UiState.Command.FetchLimits()
+	startFetchPages(maxPages, maxItems)
+	return nil
+}
+
+// handleFetchNPages asks how many pages should be fetched and merged
+func handleFetchNPages(event *tcell.EventKey) *tcell.EventKey {
+	if _, isTable := Body.(*tview.Table); !isTable || App.GetFocus() != Body || isBackgroundTaskRunning() {
+		return event
+	}
+
+	if !cmd.UiState.Command.SupportsFetchAll() {
+		logger.Logger.Warn().Str("command", cmd.UiState.Command.Name).Msg("Fetch pages is only available for token based pagination")
+		return nil
+	}
+
+	previousBody := Body
+	maxPages, maxItems := cmd.UiState.Command.FetchLimits()
+
+	form := ui.CreateInputForm(ui.InputFormProperties{
+		Title: fmt.Sprintf(" Fetch pages: %s ", cmd.UiState.Command.Name),
+		Fields: []ui.InputField{
+			{Label: "Pages", Key: "pages", DefaultValue: "5"},
+			{Label: "Max items", Key: "items", DefaultValue: strconv.Itoa(maxItems)},
+		},
+		OnSubmit: func(values map[string]string) {
+			pages := parsePositiveInt(values["pages"], 5)
+			if pages > maxPages {
+				pages = maxPages
+			}
+			Body = previousBody
+			startFetchPages(pages, parsePositiveInt(values["items"], maxItems))
+		},
+		OnCancel: func() {
+			Body = previousBody
+			updateRootView(nil)
+			App.SetFocus(Body)
+		},
+		App: App,
+	})
+
+	Body = form
+	updateRootView(nil)
+	App.SetFocus(form)
+	return nil
+}
+
+// parsePositiveInt parses a positive integer, returning fallback for empty or invalid input
+func parsePositiveInt(value string, fallback int) int {
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return fallback
+	}
+	return number
+}
+
+// startFetchPages fetches and merges pages in the background while showing progress.
+// ESC cancels the fetch and shows the pages merged so far.
+func startFetchPages(maxPages int, maxItems int) {
+	command := cmd.UiState.Command
+	resourceName := cmd.UiState.Resource.Name
+	profileName := cmd.UiState.Profile
+	startToken := cmd.UiState.CurrentPageToken
+	previousBody := Body
+
+	var fetched cmd.FetchProgress
+	var result cmd.FetchPagesResult
+	progress := func(elapsed time.Duration) string {
+		if fetched.Pages == 0 {
+			return fmt.Sprintf("Fetching up to %d pages / %d items...\n\nPress [gold]ESC[white] to cancel and keep the pages fetched so far.", maxPages, maxItems)
+		}
+		return fmt.Sprintf("Fetched [gold]%d[white] pages, [gold]%d[white] items (%s)\n\nPress [gold]ESC[white] to cancel and keep the pages fetched so far.",
+			fetched.Pages, fetched.Items, elapsed.Round(time.Second))
+	}
+
+	runBackgroundTask(fmt.Sprintf("Fetching %s", command.Name), progress, func(ctx context.Context, update func(func())) error {
+		var err error
+		result, err = command.FetchPages(ctx, resourceName, profileName, startToken, maxPages, maxItems, func(progress cmd.FetchProgress) {
+			update(func() { fetched = progress })
+		})
+		return err
+	}, func(task taskResult) {
+		// The user navigated away while fetching, drop the result
+		if !task.Visible {
+			return
+		}
+
+		if task.Err != nil {
+			logger.Logger.Error().Err(task.Err).Str("command", command.Name).Msg("Fetch pages failed")
+			showTaskResult("Fetch failed.", task.Err, previousBody)
+			return
+		}
+		if result.Output == "" {
+			Body = previousBody
+			updateRootView(nil)
+			App.SetFocus(Body)
+			return
+		}
+
+		logger.Logger.Debug().
+			Int("pages", result.Pages).
+			Int("items", result.Items).
+			Bool("cancelled", result.Cancelled).
+			Msg("Fetch pages completed")
+
+		showMergedPages(command, result)
+	})
+}
+
+// showMergedPages renders the merged output and keeps the remaining token for the next page shortcut
+func showMergedPages(command cmd.Command, result cmd.FetchPagesResult) {
+	if currentNav := peekNavigation(); currentNav != nil {
+		currentNav.PaginationToken = result.NextToken
+	}
+
+	commandParsed, body := renderCommandOutput(command, result.Output)
+
+	if table, ok := body.(*tview.Table); ok {
+		status := "all pages"
+		if result.Cancelled {
+			status = "cancelled"
+		} else if result.NextToken != "" {
+			status = "limit reached"
+		}
+		details := fmt.Sprintf("%d pages, %s", result.Pages, status)
+		if commandParsed.Summary != "" {
+			details = fmt.Sprintf("%s, %s", details, commandParsed.Summary)
+		}
+		table.SetTitle(fmt.Sprintf(" %s [%d] (%s) ", commandParsed.Command, len(commandParsed.Values), details))
+	}
+
+	if !command.RerunOnBack {
+		updateNavigationCache(result.Output, body)
+	}
+
+	Body = body
+	updateRootView(nil)
+	App.SetFocus(Body)
+}
@@ -3,9 +3,11 @@ package parser
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cmd-tools/aws-commander/cmd"
+	"github.com/cmd-tools/aws-commander/cmd/dynamodb"
 	"github.com/cmd-tools/aws-commander/logger"
 	"github.com/cmd-tools/aws-commander/ui"
 	"github.com/iancoleman/orderedmap"
@@ -17,6 +19,9 @@ type ParseCommandResult struct {
 	Header  []string
 	Values  [][]string
 	RawData []interface{}
+	Object  interface{}      // Parsed value of Parse.AttributeName, used by the key/value, tree and text views
+	Summary string           // Extra title information, e.g. scanned and returned counts of DynamoDB scans
+	Indexes []dynamodb.Index // Indexes of a DynamoDB table description, parsed with the keys type
 }
 
 func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult {
@@ -52,9 +57,15 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 		}
 	}
 
-	var parseCommandResult = ParseCommandResult{Command: command.Name}
+	summary := countSummary(jsonResult)
+	var parseCommandResult = ParseCommandResult{Command: command.Name, Summary: summary}
 	baseAttribute, exists := jsonResult.Get(command.Parse.AttributeName)
 
+	// Without attribute name the whole output is parsed (e.g. s3api head-object)
+	if command.Parse.AttributeName == "" {
+		baseAttribute, exists = *jsonResult, true
+	}
+
 	logger.Logger.Debug().
 		Str("attribute", command.Parse.AttributeName).
 		Bool("exists", exists).
@@ -68,6 +79,7 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 			Command: command.Name,
 			Header:  []string{"Info"},
 			Values:  [][]string{{fmt.Sprintf("No %s found", command.Parse.AttributeName)}},
+			Summary: summary,
 		}
 	}
 
@@ -78,9 +90,12 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 			Command: command.Name,
 			Header:  []string{"Info"},
 			Values:  [][]string{{fmt.Sprintf("No %s available", command.Parse.AttributeName)}},
+			Summary: summary,
 		}
 	}
 
+	parseCommandResult.Object = baseAttribute
+
 	switch baseAttribute.(type) {
 	case []interface{}:
 		logger.Logger.Debug().Msg("Parse command list")
@@ -93,62 +108,48 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 				Command: command.Name,
 				Header:  []string{"Info"},
 				Values:  [][]string{{"Empty - no items available"}},
+				Summary: summary,
 			}
 		}
 
+		// DynamoDB typed values are unwrapped, unless the DynamoDB JSON format is toggled on
+		unwrapTypedValues := command.Parse.ValueFormat == cmd.ValueFormatDynamoDB && !cmd.UiState.ShowDynamoDBJsonFormat
+		columnTypes := make(map[string]string)
+
+		if command.Parse.Type == "object" {
+			parseCommandResult.Header = Columns(items, command.Parse)
+		}
+
 		for i, s := range items {
 			var values []string
 			if command.Parse.Type == "object" {
+				fields, ok := objectFields(s)
+				if !ok {
+					logger.Logger.Error().
+						Interface("item", s).
+						Str("type", fmt.Sprintf("%T", s)).
+						Msg("Item is neither orderedmap nor regular map")
+					continue
+				}
+
 				// Store raw data for JSON viewer
 				parseCommandResult.RawData = append(parseCommandResult.RawData, s)
 
-				// Try orderedmap first, then regular map
-				var itemMap map[string]interface{}
-				var keys []string
-
-				if orderedItem, ok := s.(orderedmap.OrderedMap); ok {
-					// It's an orderedmap
-					keys = orderedItem.Keys()
-					if i == 0 {
-						parseCommandResult.Header = keys
+				// Items may miss attributes of the header, which leaves their cell empty
+				for _, key := range parseCommandResult.Header {
+					value, exists := fields[key]
+					if !exists {
+						values = append(values, "")
+						continue
 					}
-					for _, key := range keys {
-						value, exists := orderedItem.Get(key)
-						if exists {
-							switch value.(type) {
-							case string:
-								values = append(values, fmt.Sprintf("%v", value))
-							default:
-								bytes, _ := json.Marshal(value)
-								values = append(values, fmt.Sprintf("%v", string(bytes)))
-							}
+					if unwrapTypedValues {
+						if text, attrType, ok := ui.DynamoDBCellValue(value); ok {
+							values = append(values, text)
+							columnTypes[key] = mergeColumnType(columnTypes[key], attrType)
+							continue
 						}
 					}
-				} else if regularMap, ok := s.(map[string]interface{}); ok {
-					// It's a regular map
-					itemMap = regularMap
-					for key := range itemMap {
-						keys = append(keys, key)
-					}
-					if i == 0 {
-						parseCommandResult.Header = keys
-					}
-					for _, key := range keys {
-						value := itemMap[key]
-						switch value.(type) {
-						case string:
-							values = append(values, fmt.Sprintf("%v", value))
-						default:
-							bytes, _ := json.Marshal(value)
-							values = append(values, fmt.Sprintf("%v", string(bytes)))
-						}
-					}
-				} else {
-					logger.Logger.Error().
-						Interface("item", s).
-						Str("type", fmt.Sprintf("%T", s)).
-						Msg("Item is neither orderedmap nor regular map")
-					continue
+					values = append(values, formatCellValue(value))
 				}
 
 				parseCommandResult.Values = append(parseCommandResult.Values, values)
@@ -163,6 +164,13 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 				logger.Logger.Debug().Msg("Wrong type. Accepted types [Object, List]")
 			}
 		}
+
+		// The type of unwrapped values is shown as header suffix, e.g. "age (N)"
+		for index, key := range parseCommandResult.Header {
+			if attrType := columnTypes[key]; attrType != "" {
+				parseCommandResult.Header[index] = fmt.Sprintf("%s (%s)", key, attrType)
+			}
+		}
 	case interface{}:
 		logger.Logger.Debug().Msg("Parse command object")
 
@@ -196,159 +204,151 @@ func ParseCommand(command cmd.Command, commandOutput string) ParseCommandResult
 			}
 		}
 		parseCommandResult.Values = append(parseCommandResult.Values, values)
+		parseCommandResult.RawData = append(parseCommandResult.RawData, item)
 	default:
 		logger.Logger.Debug().Msg("Fail to Parse. Command result is not an Map or List")
 	}
 	return parseCommandResult
 }
 
-// parseTableKeys extracts partition keys, sort keys, and GSI/LSI from DynamoDB describe-table output
-// Returns indexes as selectable items instead of individual keys
-func parseTableKeys(tableAttribute interface{}) ParseCommandResult {
-	result := ParseCommandResult{
-		Command: "describe-table",
-		Header:  []string{"Index Name", "Index Type", "Keys", "Key Details"},
-		Values:  [][]string{},
+// objectFields returns the fields of a JSON object item, decoded either as orderedmap or as regular map
+func objectFields(item interface{}) (map[string]interface{}, bool) {
+	switch object := item.(type) {
+	case orderedmap.OrderedMap:
+		return object.Values(), true
+	case *orderedmap.OrderedMap:
+		return object.Values(), true
+	case map[string]interface{}:
+		return object, true
 	}
+	return nil, false
+}
 
-	tableMap, ok := tableAttribute.(orderedmap.OrderedMap)
-	if !ok {
-		logger.Logger.Error().Msg("Failed to parse table description as orderedmap")
-		return ParseCommandResult{
-			Command: "describe-table",
-			Header:  []string{"Error"},
-			Values:  [][]string{{"Failed to parse table description"}},
+// objectKeys returns the keys of a JSON object item, in document order when known
+func objectKeys(item interface{}) []string {
+	switch object := item.(type) {
+	case orderedmap.OrderedMap:
+		return object.Keys()
+	case *orderedmap.OrderedMap:
+		return object.Keys()
+	case map[string]interface{}:
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
 		}
+		sort.Strings(keys)
+		return keys
 	}
+	return nil
+}
 
-	// Get KeySchema for primary key
-	keySchemaAttr, exists := tableMap.Get("KeySchema")
-	if !exists {
-		logger.Logger.Error().Msg("KeySchema not found in table description")
-		return result
-	}
-
-	// Get AttributeDefinitions to map attribute types
-	attributeDefsAttr, _ := tableMap.Get("AttributeDefinitions")
-	attributeTypes := make(map[string]string)
-	if attributeDefs, ok := attributeDefsAttr.([]interface{}); ok {
-		for _, attr := range attributeDefs {
-			if attrMap, ok := attr.(orderedmap.OrderedMap); ok {
-				name, _ := attrMap.Get("AttributeName")
-				attrType, _ := attrMap.Get("AttributeType")
-				if name != nil && attrType != nil {
-					attributeTypes[name.(string)] = attrType.(string)
-				}
+// Columns returns the union of the keys of the object items: key columns first, then in first seen or alphabetical order.
+// Parse.Columns, when set, restricts the result to the chosen columns.
+func Columns(items []interface{}, parse cmd.Parse) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range items {
+		for _, key := range objectKeys(item) {
+			if !seen[key] {
+				seen[key] = true
+				names = append(names, key)
 			}
 		}
 	}
 
-	// Parse primary key (HASH and RANGE)
-	var primaryKeys []string
-	if keySchema, ok := keySchemaAttr.([]interface{}); ok {
-		for _, key := range keySchema {
-			if keyMap, ok := key.(orderedmap.OrderedMap); ok {
-				keyName, _ := keyMap.Get("AttributeName")
-				keyType, _ := keyMap.Get("KeyType")
-				if keyName != nil && keyType != nil {
-					attrType := attributeTypes[keyName.(string)]
-					displayKeyType := "PK"
-					if keyType.(string) == "RANGE" {
-						displayKeyType = "SK"
-					}
-					primaryKeys = append(primaryKeys, fmt.Sprintf("%s (%s:%s)", keyName.(string), displayKeyType, attrType))
-				}
-			}
-		}
+	if parse.ColumnOrder == cmd.ColumnOrderAlphabetical {
+		sort.Strings(names)
 	}
 
-	if len(primaryKeys) > 0 {
-		result.Values = append(result.Values, []string{
-			"Primary",
-			"Primary Index",
-			fmt.Sprintf("%d", len(primaryKeys)),
-			strings.Join(primaryKeys, ", "),
-		})
+	var columns []string
+	for _, key := range parse.KeyColumns {
+		if seen[key] {
+			columns = append(columns, key)
+			seen[key] = false
+		}
+	}
+	for _, name := range names {
+		if seen[name] {
+			columns = append(columns, name)
+		}
 	}
 
-	// Parse Global Secondary Indexes (GSI)
-	if gsiAttr, exists := tableMap.Get("GlobalSecondaryIndexes"); exists {
-		if gsiList, ok := gsiAttr.([]interface{}); ok {
-			for _, gsi := range gsiList {
-				if gsiMap, ok := gsi.(orderedmap.OrderedMap); ok {
-					indexName, _ := gsiMap.Get("IndexName")
-					keySchema, _ := gsiMap.Get("KeySchema")
-
-					var gsiKeys []string
-					if keySchemaList, ok := keySchema.([]interface{}); ok {
-						for _, key := range keySchemaList {
-							if keyMap, ok := key.(orderedmap.OrderedMap); ok {
-								keyName, _ := keyMap.Get("AttributeName")
-								keyType, _ := keyMap.Get("KeyType")
-								if keyName != nil && keyType != nil {
-									attrType := attributeTypes[keyName.(string)]
-									displayKeyType := "PK"
-									if keyType.(string) == "RANGE" {
-										displayKeyType = "SK"
-									}
-									gsiKeys = append(gsiKeys, fmt.Sprintf("%s (%s:%s)", keyName.(string), displayKeyType, attrType))
-								}
-							}
-						}
-					}
+	if len(parse.Columns) == 0 {
+		return columns
+	}
 
-					if len(gsiKeys) > 0 {
-						result.Values = append(result.Values, []string{
-							indexName.(string),
-							"Global Secondary Index",
-							fmt.Sprintf("%d", len(gsiKeys)),
-							strings.Join(gsiKeys, ", "),
-						})
-					}
-				}
-			}
+	chosen := make(map[string]bool)
+	for _, column := range parse.Columns {
+		chosen[column] = true
+	}
+	var visible []string
+	for _, column := range columns {
+		if chosen[column] {
+			visible = append(visible, column)
 		}
 	}
+	return visible
+}
 
-	// Parse Local Secondary Indexes (LSI)
-	if lsiAttr, exists := tableMap.Get("LocalSecondaryIndexes"); exists {
-		if lsiList, ok := lsiAttr.([]interface{}); ok {
-			for _, lsi := range lsiList {
-				if lsiMap, ok := lsi.(orderedmap.OrderedMap); ok {
-					indexName, _ := lsiMap.Get("IndexName")
-					keySchema, _ := lsiMap.Get("KeySchema")
-
-					var lsiKeys []string
-					if keySchemaList, ok := keySchema.([]interface{}); ok {
-						for _, key := range keySchemaList {
-							if keyMap, ok := key.(orderedmap.OrderedMap); ok {
-								keyName, _ := keyMap.Get("AttributeName")
-								keyType, _ := keyMap.Get("KeyType")
-								if keyName != nil && keyType != nil {
-									attrType := attributeTypes[keyName.(string)]
-									displayKeyType := "PK"
-									if keyType.(string) == "RANGE" {
-										displayKeyType = "SK"
-									}
-									lsiKeys = append(lsiKeys, fmt.Sprintf("%s (%s:%s)", keyName.(string), displayKeyType, attrType))
-								}
-							}
-						}
-					}
+// mergeColumnType combines the types of the values of a column, which DynamoDB does not enforce for non-key attributes
+func mergeColumnType(current string, attrType string) string {
+	if current == "" || current == attrType {
+		return attrType
+	}
+	return "mixed"
+}
 
-					if len(lsiKeys) > 0 {
-						result.Values = append(result.Values, []string{
-							indexName.(string),
-							"Local Secondary Index",
-							fmt.Sprintf("%d", len(lsiKeys)),
-							strings.Join(lsiKeys, ", "),
-						})
-					}
-				}
-			}
+// countSummary describes the Count and ScannedCount of DynamoDB scan and query outputs, which differ when a filter drops items
+func countSummary(jsonResult *orderedmap.OrderedMap) string {
+	count, hasCount := jsonResult.Get("Count")
+	scannedCount, hasScannedCount := jsonResult.Get("ScannedCount")
+	if !hasCount || !hasScannedCount {
+		return ""
+	}
+	return fmt.Sprintf("scanned %v / returned %v", scannedCount, count)
+}
+
+// TableTitle returns the title of the table view of a parsed result
+func TableTitle(parsedResult ParseCommandResult) string {
+	if parsedResult.Summary != "" {
+		return fmt.Sprintf(" %s [%d] (%s) ", parsedResult.Command, len(parsedResult.Values), parsedResult.Summary)
+	}
+	return fmt.Sprintf(" %s [%d] ", parsedResult.Command, len(parsedResult.Values))
+}
+
+// parseTableKeys extracts partition keys, sort keys, and GSI/LSI from DynamoDB describe-table output
+// Returns indexes as selectable items instead of individual keys, with their structured model in Indexes
+func parseTableKeys(tableAttribute interface{}) ParseCommandResult {
+	result := ParseCommandResult{
+		Command: "describe-table",
+		Header:  []string{"Index Name", "Index Type", "Keys", "Key Details"},
+		Values:  [][]string{},
+	}
+
+	var table dynamodb.TableDescription
+	description, err := json.Marshal(tableAttribute)
+	if err == nil {
+		err = json.Unmarshal(description, &table)
+	}
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to parse table description")
+		return ParseCommandResult{
+			Command: "describe-table",
+			Header:  []string{"Error"},
+			Values:  [][]string{{"Failed to parse table description"}},
 		}
 	}
 
+	result.Indexes = table.Indexes()
+	for _, index := range result.Indexes {
+		result.Values = append(result.Values, []string{
+			index.Name,
+			index.Type,
+			fmt.Sprintf("%d", len(index.Keys)),
+			index.KeyDetails(),
+		})
+	}
+
 	if len(result.Values) == 0 {
 		result.Values = [][]string{{"No indexes found", "", "", ""}}
 	}
@@ -361,6 +361,19 @@ func ParseToObject(viewType string, parsedResult ParseCommandResult, command cmd
 	case "tableView":
 		logger.Logger.Debug().Msg(fmt.Sprintf("Parse to %s", viewType))
 		return parseToTableView(parsedResult, command, commandHandler, app, restoreRootView, createHeader, createFooter, logView, isLogEnabled)
+	case "keyValueView":
+		logger.Logger.Debug().Msg(fmt.Sprintf("Parse to %s", viewType))
+		return parseToKeyValueView(parsedResult, command, commandHandler, app, restoreRootView, createHeader, createFooter, logView, isLogEnabled)
+	case "treeView":
+		logger.Logger.Debug().Msg(fmt.Sprintf("Parse to %s", viewType))
+		return parseToTreeView(parsedResult, app, createHeader, createFooter, logView, isLogEnabled)
+	case "textView":
+		logger.Logger.Debug().Msg(fmt.Sprintf("Parse to %s", viewType))
+		return ui.CreateJsonTextViewer(ui.JsonViewerProperties{
+			Title: parsedResult.Command,
+			Data:  viewerData(parsedResult),
+			App:   app,
+		})
 	default:
 		logger.Logger.Debug().Msg(fmt.Sprintf("View type '%s' not found", viewType))
 		return nil
@@ -377,7 +390,7 @@ func mapCommandHeaderToColumn(headers []string) []ui.Column {
 
 func parseToTableView(parsedResult ParseCommandResult, command cmd.Command, commandHandler func(selectedProfileName string), app *tview.Application, restoreRootView func(), createHeader func() *tview.Flex, createFooter func([]string) *tview.Table, logView *tview.TextView, isLogEnabled bool) tview.Primitive {
 	return ui.CreateCustomTableView(ui.CustomTableViewProperties{
-		Title:          fmt.Sprintf(" %s [%d] ", parsedResult.Command, len(parsedResult.Values)),
+		Title:          TableTitle(parsedResult),
 		Columns:        mapCommandHeaderToColumn(parsedResult.Header),
 		Rows:           parsedResult.Values,
 		RowData:        parsedResult.RawData,
@@ -391,3 +404,104 @@ func parseToTableView(parsedResult ParseCommandResult, command cmd.Command, comm
 		IsLogEnabled:   isLogEnabled,
 	})
 }
+
+// singleObject returns the parsed object when the result holds exactly one JSON object
+func singleObject(parsedResult ParseCommandResult) (orderedmap.OrderedMap, bool) {
+	switch object := parsedResult.Object.(type) {
+	case orderedmap.OrderedMap:
+		return object, true
+	case []interface{}:
+		if len(object) == 1 {
+			item, ok := object[0].(orderedmap.OrderedMap)
+			return item, ok
+		}
+	}
+	return orderedmap.OrderedMap{}, false
+}
+
+// viewerData returns the data shown by the tree and text views
+func viewerData(parsedResult ParseCommandResult) interface{} {
+	if object, ok := singleObject(parsedResult); ok {
+		return object
+	}
+	if parsedResult.Object != nil {
+		return parsedResult.Object
+	}
+	// Info and error results have no parsed object, show their message instead
+	return map[string]interface{}{strings.Join(parsedResult.Header, ", "): parsedResult.Values}
+}
+
+// formatCellValue renders a JSON value in a table cell: strings as-is, everything else as compact JSON
+func formatCellValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	bytes, _ := json.Marshal(value)
+	return string(bytes)
+}
+
+// parseToKeyValueView renders a single object as an attribute/value table.
+// Enter on a row opens its value in the JSON viewer. Results with many objects fall back to the table view.
+func parseToKeyValueView(parsedResult ParseCommandResult, command cmd.Command, commandHandler func(selectedProfileName string), app *tview.Application, restoreRootView func(), createHeader func() *tview.Flex, createFooter func([]string) *tview.Table, logView *tview.TextView, isLogEnabled bool) tview.Primitive {
+	object, ok := singleObject(parsedResult)
+	if !ok {
+		logger.Logger.Debug().Msg("Result is not a single object, falling back to table view")
+		return parseToTableView(parsedResult, command, commandHandler, app, restoreRootView, createHeader, createFooter, logView, isLogEnabled)
+	}
+
+	var rows [][]string
+	var rowData []interface{}
+	for _, key := range object.Keys() {
+		value, _ := object.Get(key)
+		rows = append(rows, []string{key, formatCellValue(value)})
+		rowData = append(rowData, value)
+	}
+
+	return ui.CreateCustomTableView(ui.CustomTableViewProperties{
+		Title:          fmt.Sprintf(" %s [%d attributes] ", parsedResult.Command, len(rows)),
+		Columns:        mapCommandHeaderToColumn([]string{"Attribute", "Value"}),
+		Rows:           rows,
+		RowData:        rowData,
+		Handler:        commandHandler,
+		ShowJsonViewer: true,
+		App:            app,
+		RestoreRoot:    restoreRootView,
+		CreateHeader:   createHeader,
+		CreateFooter:   createFooter,
+		LogView:        logView,
+		IsLogEnabled:   isLogEnabled,
+	})
+}
+
+// parseToTreeView renders the result in the interactive JSON tree viewer
+func parseToTreeView(parsedResult ParseCommandResult, app *tview.Application, createHeader func() *tview.Flex, createFooter func([]string) *tview.Table, logView *tview.TextView, isLogEnabled bool) tview.Primitive {
+	data := viewerData(parsedResult)
+
+	// Rebuilds the viewer after a format toggle, or when entering and leaving processed (parsed/decompressed) JSON
+	var onBack func()
+	onBack = func() {
+		dataToShow, title := data, parsedResult.Command
+		if stackLen := len(cmd.UiState.NavigationStack); stackLen > 0 {
+			currentNav := cmd.UiState.NavigationStack[stackLen-1]
+			if currentNav.Type == cmd.BreadcrumbProcessedJson && currentNav.ProcessedData != nil {
+				dataToShow, title = currentNav.ProcessedData, currentNav.Value
+			}
+		}
+
+		viewer := ui.CreateJsonTreeViewer(ui.JsonViewerProperties{
+			Title:  title,
+			Data:   dataToShow,
+			App:    app,
+			OnBack: onBack,
+		})
+		ui.ShowWithHeaderAndFooter(app, viewer, createHeader, createFooter, logView, isLogEnabled)
+	}
+	cmd.UiState.JsonViewerCallback = onBack
+
+	return ui.CreateJsonTreeViewer(ui.JsonViewerProperties{
+		Title:  parsedResult.Command,
+		Data:   data,
+		App:    app,
+		OnBack: onBack,
+	})
+}
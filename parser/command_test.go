@@ -97,3 +97,178 @@ func Test_ParseCommand_Array(t *testing.T) {
 	var jsonResult1 = ParseCommand(commandTest, awsCommandResult2)
 	fmt.Println(jsonResult1)
 }
+
+func Test_ParseCommand_WholeOutputObject(t *testing.T) {
+	var commandTest = cmd.Command{
+		Name: "head-object",
+		Parse: cmd.Parse{
+			Type:          "object",
+			AttributeName: "",
+		},
+	}
+
+	result := ParseCommand(commandTest, `{"ContentLength": 42, "ContentType": "text/html", "Metadata": {"owner": "me"}}`)
+
+	object, ok := singleObject(result)
+	if !ok {
+		t.Fatalf("Expected a single object, got %T", result.Object)
+	}
+	if keys := object.Keys(); len(keys) != 3 || keys[0] != "ContentLength" {
+		t.Errorf("Unexpected object keys: %v", keys)
+	}
+	if len(result.RawData) != 1 {
+		t.Errorf("Expected the object as raw data, got %d rows", len(result.RawData))
+	}
+
+	metadata, _ := object.Get("Metadata")
+	if value := formatCellValue(metadata); value != `{"owner":"me"}` {
+		t.Errorf("Unexpected cell value: %s", value)
+	}
+}
+
+func Test_ParseCommand_ScanSummary(t *testing.T) {
+	var commandTest = cmd.Command{
+		Name: "scan",
+		Parse: cmd.Parse{
+			Type:          "object",
+			AttributeName: "Items",
+		},
+	}
+
+	result := ParseCommand(commandTest, `{"Items": [], "Count": 0, "ScannedCount": 50}`)
+	if title := TableTitle(result); title != " scan [1] (scanned 50 / returned 0) " {
+		t.Errorf("Unexpected title: %q", title)
+	}
+
+	result = ParseCommand(commandTest, `{"Items": [{"id": {"S": "1"}}]}`)
+	if title := TableTitle(result); title != " scan [1] " {
+		t.Errorf("Unexpected title: %q", title)
+	}
+}
+
+func Test_ParseCommand_UnionHeader(t *testing.T) {
+	var commandTest = cmd.Command{
+		Name: "scan",
+		Parse: cmd.Parse{
+			Type:          "object",
+			AttributeName: "Items",
+			ColumnOrder:   cmd.ColumnOrderAlphabetical,
+			KeyColumns:    []string{"pk", "sk"},
+		},
+	}
+
+	output := `{"Items": [
+		{"name": {"S": "first"}, "sk": {"S": "1"}, "pk": {"S": "a"}},
+		{"pk": {"S": "b"}, "sk": {"S": "2"}, "age": {"N": "3"}}
+	]}`
+
+	result := ParseCommand(commandTest, output)
+
+	expectedHeader := []string{"pk", "sk", "age", "name"}
+	if fmt.Sprint(result.Header) != fmt.Sprint(expectedHeader) {
+		t.Fatalf("Got header %v, expected %v", result.Header, expectedHeader)
+	}
+	expectedRows := [][]string{
+		{`{"S":"a"}`, `{"S":"1"}`, "", `{"S":"first"}`},
+		{`{"S":"b"}`, `{"S":"2"}`, `{"N":"3"}`, ""},
+	}
+	if fmt.Sprint(result.Values) != fmt.Sprint(expectedRows) {
+		t.Errorf("Got rows %v, expected %v", result.Values, expectedRows)
+	}
+
+	commandTest.Parse.Columns = []string{"name", "pk"}
+	result = ParseCommand(commandTest, output)
+	if fmt.Sprint(result.Header) != "[pk name]" {
+		t.Errorf("Expected chosen columns only, got %v", result.Header)
+	}
+}
+
+func Test_ParseCommand_FirstSeenHeaderOrder(t *testing.T) {
+	var commandTest = cmd.Command{
+		Parse: cmd.Parse{
+			Type:          "object",
+			AttributeName: "Messages",
+		},
+	}
+
+	result := ParseCommand(commandTest, `{"Messages": [{"MessageId": "1", "Body": "x"}, {"MessageId": "2", "Attributes": {}}]}`)
+	if fmt.Sprint(result.Header) != "[MessageId Body Attributes]" {
+		t.Errorf("Unexpected header %v", result.Header)
+	}
+}
+
+func Test_ParseCommand_DynamoDBValueFormat(t *testing.T) {
+	var commandTest = cmd.Command{
+		Name: "scan",
+		Parse: cmd.Parse{
+			Type:          "object",
+			AttributeName: "Items",
+			ColumnOrder:   cmd.ColumnOrderAlphabetical,
+			ValueFormat:   cmd.ValueFormatDynamoDB,
+		},
+	}
+
+	output := `{"Items": [
+		{"id": {"S": "a"}, "n": {"N": "3"}, "ok": {"BOOL": true}, "tags": {"SS": ["x", "y"]}, "m": {"M": {"k": {"N": "1"}}}},
+		{"id": {"S": "b"}, "n": {"S": "three"}, "ok": {"NULL": true}, "l": {"L": [{"S": "z"}]}}
+	]}`
+
+	previousFormat := cmd.UiState.ShowDynamoDBJsonFormat
+	defer func() { cmd.UiState.ShowDynamoDBJsonFormat = previousFormat }()
+
+	cmd.UiState.ShowDynamoDBJsonFormat = false
+	result := ParseCommand(commandTest, output)
+	if fmt.Sprint(result.Header) != "[id (S) l (L) m (M) n (mixed) ok (mixed) tags (SS)]" {
+		t.Errorf("Unexpected header %v", result.Header)
+	}
+	expectedRows := [][]string{
+		{"a", "", `{"k":"1"}`, "3", "true", "x, y"},
+		{"b", `["z"]`, "", "three", "null", ""},
+	}
+	if fmt.Sprint(result.Values) != fmt.Sprint(expectedRows) {
+		t.Errorf("Got rows %v, expected %v", result.Values, expectedRows)
+	}
+
+	cmd.UiState.ShowDynamoDBJsonFormat = true
+	result = ParseCommand(commandTest, output)
+	if result.Header[0] != "id" || result.Values[0][0] != `{"S":"a"}` {
+		t.Errorf("Expected DynamoDB JSON cells, got %v %v", result.Header, result.Values[0])
+	}
+}
+
+func Test_ParseCommand_TableKeys(t *testing.T) {
+	var commandTest = cmd.Command{
+		Name: "describe-table",
+		Parse: cmd.Parse{
+			Type:          "keys",
+			AttributeName: "Table",
+		},
+	}
+
+	output := `{"Table": {
+		"AttributeDefinitions": [
+			{"AttributeName": "pk", "AttributeType": "S"},
+			{"AttributeName": "sk", "AttributeType": "N"},
+			{"AttributeName": "status", "AttributeType": "S"}
+		],
+		"KeySchema": [{"AttributeName": "pk", "KeyType": "HASH"}, {"AttributeName": "sk", "KeyType": "RANGE"}],
+		"GlobalSecondaryIndexes": [{
+			"IndexName": "byStatus",
+			"KeySchema": [{"AttributeName": "status", "KeyType": "HASH"}],
+			"Projection": {"ProjectionType": "KEYS_ONLY"},
+			"IndexStatus": "ACTIVE"
+		}]
+	}}`
+
+	result := ParseCommand(commandTest, output)
+	expectedRows := [][]string{
+		{"Primary", "Primary Index", "2", "pk (PK:S), sk (SK:N)"},
+		{"byStatus", "Global Secondary Index", "1", "status (PK:S)"},
+	}
+	if fmt.Sprint(result.Values) != fmt.Sprint(expectedRows) {
+		t.Errorf("Got rows %v, expected %v", result.Values, expectedRows)
+	}
+	if len(result.Indexes) != 2 || result.Indexes[1].Projection.ProjectionType != "KEYS_ONLY" || result.Indexes[1].Status != "ACTIVE" {
+		t.Errorf("Unexpected indexes %+v", result.Indexes)
+	}
+}
@@ -110,9 +110,10 @@ func filterTableRows(table *tview.Table, filter string) {
 	}
 
 	totalRows := len(cmd.UiState.OriginalTableData.Rows)
+	visibleRows := []int{0}
 
-	// Iterate through original data and show matching rows
-	for originalRow := 0; originalRow < totalRows; originalRow++ {
+	// Iterate through original data and show matching rows, the header stays in place
+	for originalRow := 1; originalRow < totalRows; originalRow++ {
 		rowData := cmd.UiState.OriginalTableData.Rows[originalRow]
 		if rowData == nil {
 			continue
@@ -140,9 +141,11 @@ func filterTableRows(table *tview.Table, filter string) {
 						SetTextColor(tcell.ColorWhite).
 						SetAlign(tview.AlignLeft))
 			}
+			visibleRows = append(visibleRows, originalRow)
 			visibleRow++
 		}
 	}
+	cmd.UiState.OriginalTableData.VisibleRows = visibleRows
 
 	// Clear remaining rows
 	rowCount := table.GetRowCount()
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// BinaryValue is a base64 encoded DynamoDB binary value (B, or an element of BS), the JSON viewer offers to decode it
+type BinaryValue string
+
+// Decodings of a binary value
+const (
+	BinaryDecodeUtf8 = "UTF-8"
+	BinaryDecodeHex  = "Hex dump"
+	BinaryDecodeGzip = "Gzip"
+	BinaryDecodeZlib = "Zlib"
+	BinaryDecodeJson = "JSON"
+)
+
+var BinaryDecodings = []string{BinaryDecodeUtf8, BinaryDecodeHex, BinaryDecodeGzip, BinaryDecodeZlib, BinaryDecodeJson}
+
+// maxDecompressedSize bounds the output of a gzip or zlib decoding, items are up to 400 KB but compressed data can expand a lot
+const maxDecompressedSize = 16 * 1024 * 1024
+
+// binaryValues marks the base64 strings of a B or BS value as binary, other values are returned as is
+func binaryValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if _, err := base64.StdEncoding.DecodeString(v); err == nil {
+			return BinaryValue(v)
+		}
+	case []interface{}:
+		elements := make([]interface{}, len(v))
+		for index, element := range v {
+			elements[index] = binaryValues(element)
+		}
+		return elements
+	}
+	return value
+}
+
+// DetectBinaryDecoding guesses the decoding of binary data from its magic number or content
+func DetectBinaryDecoding(data []byte) string {
+	switch {
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return BinaryDecodeGzip
+	case len(data) >= 2 && data[0] == 0x78 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		return BinaryDecodeZlib
+	case json.Valid(data) && (bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) || bytes.HasPrefix(bytes.TrimSpace(data), []byte("["))):
+		return BinaryDecodeJson
+	case utf8.Valid(data) && !bytes.ContainsFunc(data, isControlRune):
+		return BinaryDecodeUtf8
+	}
+	return BinaryDecodeHex
+}
+
+func isControlRune(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\r' && r != '\t'
+}
+
+// DecodeBinaryValue decodes a base64 binary value, returning the data shown by the JSON viewer and its title.
+// Decompressed data is shown as JSON when it parses, as text when it is UTF-8 and as hex dump otherwise.
+func DecodeBinaryValue(value string, decoding string) (interface{}, string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, "", fmt.Errorf("the value is not base64: %w", err)
+	}
+
+	title := fmt.Sprintf("Decoded %s", decoding)
+	switch decoding {
+	case BinaryDecodeUtf8:
+		if !utf8.Valid(data) {
+			return nil, "", fmt.Errorf("the value is not UTF-8 text")
+		}
+		return textOrJson(data), title, nil
+	case BinaryDecodeHex:
+		return hexDumpLines(data), title, nil
+	case BinaryDecodeJson:
+		var parsed interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, "", fmt.Errorf("the value is not JSON: %w", err)
+		}
+		return parsed, title, nil
+	case BinaryDecodeGzip, BinaryDecodeZlib:
+		var reader io.ReadCloser
+		if decoding == BinaryDecodeGzip {
+			reader, err = gzip.NewReader(bytes.NewReader(data))
+		} else {
+			reader, err = zlib.NewReader(bytes.NewReader(data))
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("the value is not %s data: %w", decoding, err)
+		}
+		defer reader.Close()
+
+		decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+		if err != nil {
+			return nil, "", fmt.Errorf("the value is not %s data: %w", decoding, err)
+		}
+		if len(decompressed) > maxDecompressedSize {
+			return nil, "", fmt.Errorf("the value decompresses to more than %d MB", maxDecompressedSize/1024/1024)
+		}
+		if !utf8.Valid(decompressed) {
+			return hexDumpLines(decompressed), title, nil
+		}
+		return textOrJson(decompressed), title, nil
+	}
+	return nil, "", fmt.Errorf("unknown decoding %s", decoding)
+}
+
+// textOrJson parses text holding a JSON object or array, other text is shown as content like decompressed data
+func textOrJson(data []byte) interface{} {
+	if parsed, ok := tryParseStringifiedJSON(string(data)); ok {
+		return parsed
+	}
+	return map[string]interface{}{"content": string(data)}
+}
+
+// hexDumpLines returns the lines of the hex dump of the data, offset, bytes and characters like hexdump -C
+func hexDumpLines(data []byte) []interface{} {
+	dump := strings.TrimSuffix(hex.Dump(data), "\n")
+	if dump == "" {
+		return []interface{}{}
+	}
+	lines := strings.Split(dump, "\n")
+	result := make([]interface{}, len(lines))
+	for index, line := range lines {
+		result[index] = line
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func compress(t *testing.T, decoding string, data string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if decoding == BinaryDecodeGzip {
+		writer := gzip.NewWriter(&buffer)
+		writer.Write([]byte(data))
+		writer.Close()
+	} else {
+		writer := zlib.NewWriter(&buffer)
+		writer.Write([]byte(data))
+		writer.Close()
+	}
+	return buffer.Bytes()
+}
+
+func TestDetectBinaryDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"Gzip", compress(t, BinaryDecodeGzip, "hello"), BinaryDecodeGzip},
+		{"Zlib", compress(t, BinaryDecodeZlib, "hello"), BinaryDecodeZlib},
+		{"JSON", []byte(` {"a": 1}`), BinaryDecodeJson},
+		{"Text", []byte("héllo\nworld"), BinaryDecodeUtf8},
+		{"Binary", []byte{0x00, 0x01, 0xff}, BinaryDecodeHex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if decoding := DetectBinaryDecoding(tt.data); decoding != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, decoding)
+			}
+		})
+	}
+}
+
+func TestDecodeBinaryValue(t *testing.T) {
+	encode := base64.StdEncoding.EncodeToString
+
+	data, title, err := DecodeBinaryValue(encode(compress(t, BinaryDecodeZlib, `{"id": 7}`)), BinaryDecodeZlib)
+	if err != nil || title != "Decoded Zlib" || !reflect.DeepEqual(data, map[string]interface{}{"id": float64(7)}) {
+		t.Errorf("Unexpected zlib decoding %v %q (%v)", data, title, err)
+	}
+
+	data, _, err = DecodeBinaryValue(encode(compress(t, BinaryDecodeGzip, "plain text")), BinaryDecodeGzip)
+	if err != nil || !reflect.DeepEqual(data, map[string]interface{}{"content": "plain text"}) {
+		t.Errorf("Unexpected gzip decoding %v (%v)", data, err)
+	}
+
+	data, _, err = DecodeBinaryValue(encode([]byte("AB\x00")), BinaryDecodeHex)
+	if lines, ok := data.([]interface{}); err != nil || !ok || len(lines) != 1 || !strings.HasPrefix(lines[0].(string), "00000000  41 42 00") {
+		t.Errorf("Unexpected hex dump %v (%v)", data, err)
+	}
+
+	if _, _, err := DecodeBinaryValue(encode([]byte("not compressed")), BinaryDecodeGzip); err == nil {
+		t.Errorf("Expected an error decompressing plain text")
+	}
+	if _, _, err := DecodeBinaryValue(encode([]byte{0xff, 0xfe}), BinaryDecodeUtf8); err == nil {
+		t.Errorf("Expected an error for invalid UTF-8")
+	}
+	if _, _, err := DecodeBinaryValue(encode([]byte("{")), BinaryDecodeJson); err == nil {
+		t.Errorf("Expected an error for invalid JSON")
+	}
+}
+
+func TestConvertDynamoDBBinaryValues(t *testing.T) {
+	var item interface{}
+	json.Unmarshal([]byte(`{"payload": {"B": "AAE="}, "chunks": {"BS": ["AAE=", "Ag=="]}, "name": {"S": "AAE="}}`), &item)
+
+	converted := convertDynamoDBToRegularJSON(item).(map[string]interface{})
+	if converted["payload"] != BinaryValue("AAE=") || converted["name"] != "AAE=" {
+		t.Errorf("Expected only B values to be binary, got %#v", converted)
+	}
+	if chunks := converted["chunks"].([]interface{}); chunks[1] != BinaryValue("Ag==") {
+		t.Errorf("Expected BS elements to be binary, got %#v", chunks)
+	}
+
+	// Binary values stay base64 strings in JSON, e.g. when copied to the clipboard
+	output, _ := json.Marshal(converted["payload"])
+	if string(output) != `"AAE="` {
+		t.Errorf("Unexpected JSON %s", output)
+	}
+}
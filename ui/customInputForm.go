@@ -1,15 +1,23 @@
 package ui
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
 type InputFormProperties struct {
 	Title        string
+	Notes        []string // Information shown above the fields, e.g. what the results of a query will miss
 	Fields       []InputField
+	OnValidate   func(values map[string]string) error // Errors are shown in the form and prevent OnSubmit
+	InitialError string                               // Error shown when the form opens, e.g. a failed submit. Requires OnValidate
 	OnSubmit     func(values map[string]string)
 	OnCancel     func()
+	Buttons      []FormButton // Extra buttons shown between Submit and Cancel
 	App          *tview.Application
 	PreviousView tview.Primitive
 }
@@ -18,6 +26,16 @@ type InputField struct {
 	Label        string
 	Key          string
 	DefaultValue string
+	Options      []string // When set, the field is a drop down with these options
+	Checkbox     bool     // When set, the field is a checkbox with value "true" or "false"
+	Multiline    bool     // When set, the field is a text area
+	Height       int      // Lines of a multiline field, 0 for the default height
+}
+
+// FormButton is an extra form button receiving the current field values
+type FormButton struct {
+	Label   string
+	OnClick func(values map[string]string)
 }
 
 func CreateInputForm(properties InputFormProperties) *tview.Form {
@@ -27,21 +45,83 @@ func CreateInputForm(properties InputFormProperties) *tview.Form {
 
 	values := make(map[string]string)
 
+	if len(properties.Notes) > 0 {
+		notesView := tview.NewTextView().SetDynamicColors(true).SetScrollable(false)
+		notesView.SetText(fmt.Sprintf("[yellow]%s", tview.Escape(strings.Join(properties.Notes, "\n"))))
+		notesView.SetSize(len(properties.Notes), 0)
+		form.AddFormItem(notesView)
+	}
+
 	// Add input fields
 	for _, field := range properties.Fields {
 		fieldKey := field.Key
+		values[fieldKey] = field.DefaultValue
+
+		if len(field.Options) > 0 {
+			initialOption := 0
+			for index, option := range field.Options {
+				if option == field.DefaultValue {
+					initialOption = index
+				}
+			}
+			values[fieldKey] = field.Options[initialOption]
+			form.AddDropDown(field.Label, field.Options, initialOption, func(option string, optionIndex int) {
+				values[fieldKey] = option
+			})
+			continue
+		}
+
+		if field.Checkbox {
+			form.AddCheckbox(field.Label, field.DefaultValue == "true", func(checked bool) {
+				values[fieldKey] = strconv.FormatBool(checked)
+			})
+			continue
+		}
+
+		if field.Multiline {
+			form.AddTextArea(field.Label, field.DefaultValue, 0, field.Height, 0, func(text string) {
+				values[fieldKey] = text
+			})
+			continue
+		}
+
 		form.AddInputField(field.Label, field.DefaultValue, 0, nil, func(text string) {
 			values[fieldKey] = text
 		})
 	}
 
+	// Validation errors are shown below the fields
+	var errorView *tview.TextView
+	if properties.OnValidate != nil {
+		errorView = tview.NewTextView().SetDynamicColors(true).SetScrollable(false)
+		errorView.SetSize(1, 0)
+		if properties.InitialError != "" {
+			errorView.SetText(fmt.Sprintf("[red]%s", tview.Escape(properties.InitialError)))
+		}
+		form.AddFormItem(errorView)
+	}
+
 	// Add buttons
 	form.AddButton("Submit", func() {
+		if properties.OnValidate != nil {
+			if err := properties.OnValidate(values); err != nil {
+				errorView.SetText(fmt.Sprintf("[red]%s", tview.Escape(err.Error())))
+				return
+			}
+			errorView.SetText("")
+		}
 		if properties.OnSubmit != nil {
 			properties.OnSubmit(values)
 		}
 	})
 
+	for _, button := range properties.Buttons {
+		onClick := button.OnClick
+		form.AddButton(button.Label, func() {
+			onClick(values)
+		})
+	}
+
 	form.AddButton("Cancel", func() {
 		if properties.OnCancel != nil {
 			properties.OnCancel()
@@ -0,0 +1,306 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/iancoleman/orderedmap"
+	"github.com/rivo/tview"
+)
+
+type JsonDiffKind string
+
+const (
+	JsonDiffUnchanged JsonDiffKind = "unchanged"
+	JsonDiffAdded     JsonDiffKind = "added"   // Only in the right value
+	JsonDiffRemoved   JsonDiffKind = "removed" // Only in the left value
+	JsonDiffChanged   JsonDiffKind = "changed"
+)
+
+// JsonDiffNode is a node of the diff tree of two JSON values, objects and arrays have children
+type JsonDiffNode struct {
+	Key      string // Attribute name or array index ("[0]"), empty for the root
+	Kind     JsonDiffKind
+	Old      interface{} // Left value of a leaf
+	New      interface{} // Right value of a leaf
+	Children []*JsonDiffNode
+}
+
+// JsonDiffChange is a path added, removed or changed between two JSON values
+type JsonDiffChange struct {
+	Path string // e.g. "address.lines[1]"
+	Kind JsonDiffKind
+	Old  interface{}
+	New  interface{}
+}
+
+type JsonDiffViewerProperties struct {
+	Title          string
+	LeftTitle      string
+	RightTitle     string
+	Left           interface{}
+	Right          interface{}
+	DynamoDBFormat bool // Format shown first, 'v' toggles it
+}
+
+// DiffJson compares two JSON values path by path. Stringified JSON objects and arrays are compared by their content.
+func DiffJson(left interface{}, right interface{}) *JsonDiffNode {
+	return diffJsonValues("", left, right)
+}
+
+func diffJsonValues(key string, left interface{}, right interface{}) *JsonDiffNode {
+	left = expandStringifiedJson(left)
+	right = expandStringifiedJson(right)
+
+	leftKeys, leftObject, isLeftObject := jsonObject(left)
+	rightKeys, rightObject, isRightObject := jsonObject(right)
+	if isLeftObject && isRightObject {
+		node := &JsonDiffNode{Key: key, Kind: JsonDiffUnchanged}
+		for _, name := range leftKeys {
+			if rightValue, exists := rightObject[name]; exists {
+				node.addChild(diffJsonValues(name, leftObject[name], rightValue))
+			} else {
+				node.addChild(oneSidedJsonNode(name, leftObject[name], JsonDiffRemoved))
+			}
+		}
+		for _, name := range rightKeys {
+			if _, exists := leftObject[name]; !exists {
+				node.addChild(oneSidedJsonNode(name, rightObject[name], JsonDiffAdded))
+			}
+		}
+		node.keepEmptyValues(left, right)
+		return node
+	}
+
+	leftList, isLeftList := left.([]interface{})
+	rightList, isRightList := right.([]interface{})
+	if isLeftList && isRightList {
+		node := &JsonDiffNode{Key: key, Kind: JsonDiffUnchanged}
+		for index := 0; index < max(len(leftList), len(rightList)); index++ {
+			name := fmt.Sprintf("[%d]", index)
+			switch {
+			case index >= len(rightList):
+				node.addChild(oneSidedJsonNode(name, leftList[index], JsonDiffRemoved))
+			case index >= len(leftList):
+				node.addChild(oneSidedJsonNode(name, rightList[index], JsonDiffAdded))
+			default:
+				node.addChild(diffJsonValues(name, leftList[index], rightList[index]))
+			}
+		}
+		node.keepEmptyValues(left, right)
+		return node
+	}
+
+	node := &JsonDiffNode{Key: key, Kind: JsonDiffUnchanged, Old: left, New: right}
+	if !reflect.DeepEqual(plainJsonValue(left), plainJsonValue(right)) {
+		node.Kind = JsonDiffChanged
+	}
+	return node
+}
+
+// addChild adds a child node, a parent of differences is changed
+func (node *JsonDiffNode) addChild(child *JsonDiffNode) {
+	node.Children = append(node.Children, child)
+	if child.Kind != JsonDiffUnchanged {
+		node.Kind = JsonDiffChanged
+	}
+}
+
+// keepEmptyValues keeps the values of empty objects and arrays, shown as leaves
+func (node *JsonDiffNode) keepEmptyValues(left interface{}, right interface{}) {
+	if len(node.Children) == 0 {
+		node.Old, node.New = left, right
+	}
+}
+
+// oneSidedJsonNode is a value existing on one side only, its nodes all have the same kind
+func oneSidedJsonNode(key string, value interface{}, kind JsonDiffKind) *JsonDiffNode {
+	value = expandStringifiedJson(value)
+	node := &JsonDiffNode{Key: key, Kind: kind}
+
+	if keys, object, ok := jsonObject(value); ok {
+		for _, name := range keys {
+			node.Children = append(node.Children, oneSidedJsonNode(name, object[name], kind))
+		}
+	} else if list, ok := value.([]interface{}); ok {
+		for index, item := range list {
+			node.Children = append(node.Children, oneSidedJsonNode(fmt.Sprintf("[%d]", index), item, kind))
+		}
+	}
+	if len(node.Children) == 0 && kind == JsonDiffAdded {
+		node.New = value
+	} else if len(node.Children) == 0 {
+		node.Old = value
+	}
+	return node
+}
+
+// Changes lists the differences by path, an added or removed object is one change
+func (node *JsonDiffNode) Changes() []JsonDiffChange {
+	var changes []JsonDiffChange
+	node.collectChanges("", &changes)
+	return changes
+}
+
+func (node *JsonDiffNode) collectChanges(path string, changes *[]JsonDiffChange) {
+	if node.Key != "" && !strings.HasPrefix(node.Key, "[") && path != "" {
+		path += "."
+	}
+	path += node.Key
+
+	switch {
+	case node.Kind == JsonDiffUnchanged:
+		return
+	case node.Kind != JsonDiffChanged || len(node.Children) == 0:
+		*changes = append(*changes, JsonDiffChange{Path: path, Kind: node.Kind, Old: node.Old, New: node.New})
+	default:
+		for _, child := range node.Children {
+			child.collectChanges(path, changes)
+		}
+	}
+}
+
+// Counts returns the number of added, removed and changed paths
+func (node *JsonDiffNode) Counts() (int, int, int) {
+	counts := map[JsonDiffKind]int{}
+	for _, change := range node.Changes() {
+		counts[change.Kind]++
+	}
+	return counts[JsonDiffAdded], counts[JsonDiffRemoved], counts[JsonDiffChanged]
+}
+
+// jsonObject returns the keys and values of a JSON object, ordered maps keep their order and other maps are sorted
+func jsonObject(value interface{}) ([]string, map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case orderedmap.OrderedMap:
+		return v.Keys(), v.Values(), true
+	case *orderedmap.OrderedMap:
+		return v.Keys(), v.Values(), true
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys, v, true
+	}
+	return nil, nil, false
+}
+
+// plainJsonValue converts ordered maps to maps, so equal values compare equal whatever their key order
+func plainJsonValue(value interface{}) interface{} {
+	if _, object, ok := jsonObject(value); ok {
+		plain := make(map[string]interface{}, len(object))
+		for key, item := range object {
+			plain[key] = plainJsonValue(item)
+		}
+		return plain
+	}
+	if list, ok := value.([]interface{}); ok {
+		plain := make([]interface{}, len(list))
+		for index, item := range list {
+			plain[index] = plainJsonValue(item)
+		}
+		return plain
+	}
+	return value
+}
+
+// expandStringifiedJson parses strings holding a JSON object or array, e.g. an SQS message body
+func expandStringifiedJson(value interface{}) interface{} {
+	if text, ok := value.(string); ok {
+		if parsed, ok := tryParseStringifiedJSON(text); ok {
+			return parsed
+		}
+	}
+	return value
+}
+
+// CreateJsonDiffViewer shows the diff of two JSON values as a tree, added paths in green, removed in red and changed in yellow
+func CreateJsonDiffViewer(properties JsonDiffViewerProperties) *tview.TreeView {
+	tree := tview.NewTreeView()
+	tree.SetBorder(true).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tview.Styles.BorderColor).
+		SetBackgroundColor(tview.Styles.PrimitiveBackgroundColor)
+
+	dynamoDBFormat := properties.DynamoDBFormat
+	render := func() {
+		left, right := properties.Left, properties.Right
+		if !dynamoDBFormat {
+			left = convertDynamoDBToRegularJSON(left)
+			right = convertDynamoDBToRegularJSON(right)
+		}
+		diff := DiffJson(left, right)
+
+		root := tview.NewTreeNode(fmt.Sprintf("[red]- %s[-] / [green]+ %s", tview.Escape(properties.LeftTitle), tview.Escape(properties.RightTitle))).
+			SetColor(tcell.ColorGold).
+			SetExpanded(true)
+		for _, child := range diff.Children {
+			buildJsonDiffTree(child, root)
+		}
+		if len(diff.Children) == 0 {
+			buildJsonDiffTree(diff, root)
+		}
+		tree.SetRoot(root).SetCurrentNode(root)
+
+		added, removed, changed := diff.Counts()
+		format := "plain"
+		if dynamoDBFormat {
+			format = "DynamoDB JSON"
+		}
+		tree.SetTitle(fmt.Sprintf(" %s (+%d -%d ~%d, %s) ", properties.Title, added, removed, changed, format))
+	}
+	render()
+
+	// 'v' switches between the DynamoDB and plain format of both values
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'v' {
+			dynamoDBFormat = !dynamoDBFormat
+			render()
+			return nil
+		}
+		return event
+	})
+
+	return tree
+}
+
+func buildJsonDiffTree(diff *JsonDiffNode, parent *tview.TreeNode) {
+	markers := map[JsonDiffKind]string{JsonDiffUnchanged: "  ", JsonDiffAdded: "+ ", JsonDiffRemoved: "- ", JsonDiffChanged: "~ "}
+	colors := map[JsonDiffKind]tcell.Color{JsonDiffUnchanged: tcell.ColorWhite, JsonDiffAdded: tcell.ColorGreen, JsonDiffRemoved: tcell.ColorRed, JsonDiffChanged: tcell.ColorYellow}
+
+	text := markers[diff.Kind] + tview.Escape(diff.Key)
+	if len(diff.Children) == 0 {
+		switch diff.Kind {
+		case JsonDiffAdded:
+			text += ": " + jsonDiffValueText(diff.New)
+		case JsonDiffChanged:
+			text += fmt.Sprintf(": %s → %s", jsonDiffValueText(diff.Old), jsonDiffValueText(diff.New))
+		default:
+			text += ": " + jsonDiffValueText(diff.Old)
+		}
+	}
+
+	node := tview.NewTreeNode(text).
+		SetColor(colors[diff.Kind]).
+		SetSelectable(true).
+		SetExpanded(true)
+	parent.AddChild(node)
+	for _, child := range diff.Children {
+		buildJsonDiffTree(child, node)
+	}
+}
+
+// jsonDiffValueText renders a leaf value as compact JSON
+func jsonDiffValueText(value interface{}) string {
+	text, err := json.Marshal(value)
+	if err != nil {
+		return tview.Escape(fmt.Sprintf("%v", value))
+	}
+	return tview.Escape(string(text))
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+func TestDiffJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     string
+		right    string
+		expected []JsonDiffChange
+	}{
+		{
+			name:     "Equal items",
+			left:     `{"id": "1", "tags": ["a", "b"], "address": {"city": "Rome"}}`,
+			right:    `{"address": {"city": "Rome"}, "tags": ["a", "b"], "id": "1"}`,
+			expected: nil,
+		},
+		{
+			name:  "Added, removed and changed attributes",
+			left:  `{"id": "1", "status": "active", "legacy": true}`,
+			right: `{"id": "1", "status": "closed", "note": "moved"}`,
+			expected: []JsonDiffChange{
+				{Path: "legacy", Kind: JsonDiffRemoved, Old: true},
+				{Path: "status", Kind: JsonDiffChanged, Old: "active", New: "closed"},
+				{Path: "note", Kind: JsonDiffAdded, New: "moved"},
+			},
+		},
+		{
+			name:  "Nested paths and list items",
+			left:  `{"address": {"lines": ["1 Main St", "Floor 2"]}}`,
+			right: `{"address": {"lines": ["1 Main St", "Floor 3", "Door B"]}}`,
+			expected: []JsonDiffChange{
+				{Path: "address.lines[1]", Kind: JsonDiffChanged, Old: "Floor 2", New: "Floor 3"},
+				{Path: "address.lines[2]", Kind: JsonDiffAdded, New: "Door B"},
+			},
+		},
+		{
+			name:  "Added object is one change",
+			left:  `{"id": "1"}`,
+			right: `{"id": "1", "address": {"city": "Rome"}}`,
+			expected: []JsonDiffChange{
+				{Path: "address", Kind: JsonDiffAdded},
+			},
+		},
+		{
+			name:  "Type change",
+			left:  `{"count": "1"}`,
+			right: `{"count": {"N": "1"}}`,
+			expected: []JsonDiffChange{
+				{Path: "count", Kind: JsonDiffChanged, Old: "1", New: map[string]interface{}{"N": "1"}},
+			},
+		},
+		{
+			name:  "Stringified JSON bodies",
+			left:  `{"Body": "{\"orderId\": 7, \"total\": 10}"}`,
+			right: `{"Body": "{\"orderId\": 7, \"total\": 12}"}`,
+			expected: []JsonDiffChange{
+				{Path: "Body.total", Kind: JsonDiffChanged, Old: float64(10), New: float64(12)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var left, right map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.left), &left); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.right), &right); err != nil {
+				t.Fatal(err)
+			}
+
+			changes := DiffJson(left, right).Changes()
+			if !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("Expected changes %v, got %v", tt.expected, changes)
+			}
+		})
+	}
+}
+
+func TestDiffJsonOrderedMaps(t *testing.T) {
+	// Rows of a command output are ordered maps
+	left := orderedmap.New()
+	if err := json.Unmarshal([]byte(`{"pk": {"S": "user#1"}, "age": {"N": "30"}, "tags": {"SS": ["a"]}}`), left); err != nil {
+		t.Fatal(err)
+	}
+	right := orderedmap.New()
+	if err := json.Unmarshal([]byte(`{"pk": {"S": "user#1"}, "age": {"N": "31"}}`), right); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := DiffJson(*left, *right)
+	expected := []JsonDiffChange{
+		{Path: "age.N", Kind: JsonDiffChanged, Old: "30", New: "31"},
+		{Path: "tags", Kind: JsonDiffRemoved},
+	}
+	if changes := diff.Changes(); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+
+	if added, removed, changed := diff.Counts(); added != 0 || removed != 1 || changed != 1 {
+		t.Errorf("Expected 0 added, 1 removed and 1 changed, got %d, %d and %d", added, removed, changed)
+	}
+
+	// The plain format compares the values of the attributes
+	plainChanges := DiffJson(convertDynamoDBToRegularJSON(*left), convertDynamoDBToRegularJSON(*right)).Changes()
+	if len(plainChanges) != 2 || plainChanges[0].Path != "age" || plainChanges[1].Path != "tags" {
+		t.Errorf("Expected age and tags to differ, got %v", plainChanges)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/atotto/clipboard"
@@ -49,12 +50,12 @@ func convertDynamoDBToRegularJSON(data interface{}) interface{} {
 						}
 						return result
 					}
-				case "SS", "NS", "BS":
-					// String set, number set, binary set - return as array
-					return val
-				case "B":
-					// Binary data - keep as is
+				case "SS", "NS":
+					// String set, number set - return as array
 					return val
+				case "B", "BS":
+					// Binary data and binary set - base64 strings the viewer can decode
+					return binaryValues(val)
 				}
 			}
 		}
@@ -100,10 +101,10 @@ func convertDynamoDBToRegularJSON(data interface{}) interface{} {
 					}
 					return result
 				}
-			case "SS", "NS", "BS":
-				return val
-			case "B":
+			case "SS", "NS":
 				return val
+			case "B", "BS":
+				return binaryValues(val)
 			}
 		}
 		// Not a DynamoDB typed value, process as regular ordered map
@@ -147,6 +148,54 @@ func convertMapValue(val interface{}) interface{} {
 	return result
 }
 
+var dynamoDBTypes = map[string]bool{"S": true, "N": true, "B": true, "BOOL": true, "NULL": true, "M": true, "L": true, "SS": true, "NS": true, "BS": true}
+
+// dynamoDBTypedValue returns the type and the value of a DynamoDB typed value ({"S": "foo"})
+func dynamoDBTypedValue(value interface{}) (string, interface{}, bool) {
+	switch v := value.(type) {
+	case orderedmap.OrderedMap:
+		if keys := v.Keys(); len(keys) == 1 && dynamoDBTypes[keys[0]] {
+			typed, _ := v.Get(keys[0])
+			return keys[0], typed, true
+		}
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for key, typed := range v {
+				if dynamoDBTypes[key] {
+					return key, typed, true
+				}
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// DynamoDBCellValue formats a DynamoDB typed value as plain table cell text and returns its type.
+// Sets become comma separated lists, maps and lists compact JSON.
+func DynamoDBCellValue(value interface{}) (string, string, bool) {
+	attrType, typed, ok := dynamoDBTypedValue(value)
+	if !ok {
+		return "", "", false
+	}
+
+	switch attrType {
+	case "NULL":
+		return "null", attrType, true
+	case "SS", "NS", "BS":
+		if items, ok := typed.([]interface{}); ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = fmt.Sprintf("%v", item)
+			}
+			return strings.Join(parts, ", "), attrType, true
+		}
+	case "M", "L":
+		bytes, _ := json.Marshal(convertDynamoDBToRegularJSON(value))
+		return string(bytes), attrType, true
+	}
+	return fmt.Sprintf("%v", typed), attrType, true
+}
+
 type JsonViewerProperties struct {
 	Title  string
 	Data   interface{}
@@ -295,6 +344,13 @@ func CreateJsonTreeViewer(properties JsonViewerProperties) *tview.TreeView {
 			if currentNode != nil && len(currentNode.GetChildren()) == 0 {
 				// This is a leaf node (has a value)
 				nodeText := currentNode.GetText()
+
+				// Binary values are decoded the way the user chooses
+				if binary, ok := currentNode.GetReference().(BinaryValue); ok {
+					showBinaryDecodings(properties, tree, nodeText, string(binary))
+					return nil
+				}
+
 				value := extractValueFromNode(nodeText)
 
 				var newData interface{}
@@ -321,39 +377,7 @@ func CreateJsonTreeViewer(properties JsonViewerProperties) *tview.TreeView {
 				}
 
 				if processed {
-					// Store the processed data in navigation stack (not global)
-					navState := cmd.NavigationState{
-						Type:          cmd.BreadcrumbProcessedJson,
-						Value:         newTitle,
-						ProcessedData: newData,
-					}
-
-					// Store the current node text for focus restoration
-					cmd.UiState.SelectedNodeText = nodeText
-
-					// Add breadcrumb and navigation state for the expanded view
-					cmd.UiState.Breadcrumbs = append(cmd.UiState.Breadcrumbs, newTitle)
-					cmd.UiState.NavigationStack = append(cmd.UiState.NavigationStack, navState)
-
-					// Reset JSON format for parsed/decompressed data (not DynamoDB format)
-					cmd.UiState.ShowDynamoDBJsonFormat = false
-
-					// Create a new JSON viewer with the processed data
-					newViewer := CreateJsonTreeViewer(JsonViewerProperties{
-						Title:  newTitle,
-						Data:   newData,
-						App:    properties.App,
-						OnBack: properties.OnBack,
-					})
-
-					// Rebuild view with header/footer if callback is provided
-					if properties.OnBack != nil {
-						properties.OnBack()
-					} else {
-						properties.App.SetRoot(newViewer, true)
-						properties.App.SetFocus(newViewer)
-					}
-
+					showProcessedJson(properties, nodeText, newTitle, newData)
 					return nil
 				}
 			}
@@ -364,6 +388,82 @@ func CreateJsonTreeViewer(properties JsonViewerProperties) *tview.TreeView {
 	return tree
 }
 
+// showProcessedJson opens parsed, decompressed or decoded data as a new navigation level of the viewer
+func showProcessedJson(properties JsonViewerProperties, nodeText string, title string, data interface{}) {
+	// Store the processed data in navigation stack (not global)
+	navState := cmd.NavigationState{
+		Type:          cmd.BreadcrumbProcessedJson,
+		Value:         title,
+		ProcessedData: data,
+	}
+
+	// Store the current node text for focus restoration
+	cmd.UiState.SelectedNodeText = nodeText
+
+	// Add breadcrumb and navigation state for the expanded view
+	cmd.UiState.Breadcrumbs = append(cmd.UiState.Breadcrumbs, title)
+	cmd.UiState.NavigationStack = append(cmd.UiState.NavigationStack, navState)
+
+	// Reset JSON format for parsed/decompressed data (not DynamoDB format)
+	cmd.UiState.ShowDynamoDBJsonFormat = false
+
+	// Create a new JSON viewer with the processed data
+	newViewer := CreateJsonTreeViewer(JsonViewerProperties{
+		Title:  title,
+		Data:   data,
+		App:    properties.App,
+		OnBack: properties.OnBack,
+	})
+
+	// Rebuild view with header/footer if callback is provided
+	if properties.OnBack != nil {
+		properties.OnBack()
+	} else {
+		properties.App.SetRoot(newViewer, true)
+		properties.App.SetFocus(newViewer)
+	}
+}
+
+// showBinaryDecodings asks how to decode a binary value, the decoding detected from its content is preselected.
+// A failed decoding is logged and the viewer shown again.
+func showBinaryDecodings(properties JsonViewerProperties, tree *tview.TreeView, nodeText string, value string) {
+	data, _ := base64.StdEncoding.DecodeString(value)
+	detected := DetectBinaryDecoding(data)
+
+	restore := func() {
+		if properties.OnBack != nil {
+			cmd.UiState.SelectedNodeText = nodeText
+			properties.OnBack()
+		} else {
+			properties.App.SetRoot(tree, true)
+			properties.App.SetFocus(tree)
+		}
+	}
+
+	buttons := append(slices.Clone(BinaryDecodings), "Cancel")
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Decode the binary value (%d bytes) as\n\nDetected: %s", len(data), detected)).
+		SetBackgroundColor(tcell.ColorDefault).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if !slices.Contains(BinaryDecodings, buttonLabel) {
+				restore()
+				return
+			}
+			decoded, title, err := DecodeBinaryValue(value, buttonLabel)
+			if err != nil {
+				logger.Logger.Error().Err(err).Str("decoding", buttonLabel).Msg("Unable to decode the binary value")
+				restore()
+				return
+			}
+			showProcessedJson(properties, nodeText, title, decoded)
+		})
+	modal.SetFocus(slices.Index(buttons, detected))
+
+	properties.App.SetRoot(modal, true)
+	properties.App.SetFocus(modal)
+}
+
 // CreateJsonTextViewer creates a formatted text view for JSON data
 func CreateJsonTextViewer(properties JsonViewerProperties) *tview.TextView {
 	textView := tview.NewTextView().
@@ -388,12 +488,37 @@ func CreateJsonTextViewer(properties JsonViewerProperties) *tview.TextView {
 	return textView
 }
 
+// ShowWithHeaderAndFooter sets a viewer as application root, framed by header, footer and log view like the main layout
+func ShowWithHeaderAndFooter(app *tview.Application, viewer tview.Primitive, createHeader func() *tview.Flex, createFooter func([]string) *tview.Table, logView *tview.TextView, isLogEnabled bool) {
+	if createHeader == nil || createFooter == nil {
+		app.SetRoot(viewer, true)
+		app.SetFocus(viewer)
+		return
+	}
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(createHeader(), 7, 2, false).
+		AddItem(viewer, 0, 1, true).
+		AddItem(createFooter(cmd.UiState.Breadcrumbs), 2, 2, false)
+
+	if isLogEnabled && logView != nil {
+		view.AddItem(logView, 8, 3, false)
+	}
+
+	app.SetRoot(view, true)
+	app.SetFocus(viewer)
+}
+
 func buildJsonTree(data interface{}, parent *tview.TreeNode) {
 	switch v := data.(type) {
 	case orderedmap.OrderedMap:
 		// Handle orderedmap.OrderedMap type
-		for _, key := range v.Keys() {
+		keys := v.Keys()
+		for _, key := range keys {
 			val, _ := v.Get(key)
+			if len(keys) == 1 && (key == "B" || key == "BS") {
+				val = binaryValues(val)
+			}
 			node := tview.NewTreeNode(fmt.Sprintf("[yellow]%s", key)).
 				SetColor(tcell.ColorYellow).
 				SetSelectable(true).
@@ -403,6 +528,9 @@ func buildJsonTree(data interface{}, parent *tview.TreeNode) {
 		}
 	case map[string]interface{}:
 		for key, val := range v {
+			if len(v) == 1 && (key == "B" || key == "BS") {
+				val = binaryValues(val)
+			}
 			node := tview.NewTreeNode(fmt.Sprintf("[yellow]%s", key)).
 				SetColor(tcell.ColorYellow).
 				SetSelectable(true).
@@ -422,6 +550,11 @@ func buildJsonTree(data interface{}, parent *tview.TreeNode) {
 	case string:
 		parent.SetText(fmt.Sprintf("%s: [green]\"%v\"", parent.GetText(), v))
 		parent.SetColor(tcell.ColorWhite)
+	case BinaryValue:
+		// Enter offers to decode the value, the node keeps it as reference
+		parent.SetText(fmt.Sprintf("%s: [green]\"%v\" [gray](binary)", parent.GetText(), v))
+		parent.SetColor(tcell.ColorWhite)
+		parent.SetReference(v)
 	case float64, int, int64:
 		parent.SetText(fmt.Sprintf("%s: [white]%v", parent.GetText(), v))
 		parent.SetColor(tcell.ColorWhite)
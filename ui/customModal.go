@@ -16,21 +16,24 @@ type ModalProperties struct {
 	RightChoice ModalChoice
 }
 
+// CreateModal shows a question with two choices, ESC picks the right one (the cancel choice by convention).
+// Without a right choice the modal only shows the left one, picked by ESC too.
 func CreateModal(properties ModalProperties, currentFlex *tview.Flex) *tview.Modal {
+	buttons := []string{properties.LeftChoice.Name}
+	if properties.RightChoice.Name != "" {
+		buttons = append(buttons, properties.RightChoice.Name)
+	}
+
 	return tview.NewModal().
 		SetText(properties.Title).
 		SetBackgroundColor(tcell.ColorDefault).
-		AddButtons([]string{properties.LeftChoice.Name, properties.RightChoice.Name}).
+		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			switch buttonLabel {
-			case properties.LeftChoice.Name:
-				properties.LeftChoice.Handler(currentFlex)
-				break
-			case properties.RightChoice.Name:
+			switch {
+			case buttonLabel == properties.LeftChoice.Name || properties.RightChoice.Handler == nil:
 				properties.LeftChoice.Handler(currentFlex)
-				break
 			default:
-				panic("Unexpected choice")
+				properties.RightChoice.Handler(currentFlex)
 			}
 		})
 }
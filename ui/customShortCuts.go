@@ -76,12 +76,12 @@ func CreateCustomShortCutsView(App *tview.Application, properties CustomShortCut
 	}
 
 	App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		// Check if focus is on an input field (Form or InputField)
+		// Check if focus is on an input field (Form or InputField) or a form button
 		// If so, allow all characters to pass through without handling shortcuts
 		focus := App.GetFocus()
 		if focus != nil {
 			switch focus.(type) {
-			case *tview.Form, *tview.InputField:
+			case *tview.Form, *tview.InputField, *tview.DropDown, *tview.Checkbox, *tview.TextArea, *tview.Button:
 				// Allow all input to pass through when focus is on input fields
 				return event
 			}
@@ -85,6 +85,7 @@ func CreateCustomTableView(properties CustomTableViewProperties) *tview.Table {
 		// Handle clipboard copy with 'y' (yank) or Ctrl+C
 		if event.Rune() == 'y' || event.Key() == tcell.KeyCtrlC {
 			row, _ := table.GetSelection()
+			row = cmd.UiState.OriginalRow(row)
 			if row > 0 && row <= len(properties.Rows) {
 				// Copy the entire row as tab-separated values
 				rowData := properties.Rows[row-1]
@@ -102,6 +103,8 @@ func CreateCustomTableView(properties CustomTableViewProperties) *tview.Table {
 
 		if event.Key() == tcell.KeyEnter {
 			row, _ := table.GetSelection()
+			// A search filter moves matching rows up, RowData keeps the original order
+			row = cmd.UiState.OriginalRow(row)
 
 			// Handle JSON viewer if enabled and we have data
 			if properties.ShowJsonViewer && len(properties.RowData) > 0 && properties.App != nil && row > 0 && row-1 < len(properties.RowData) {
@@ -111,6 +114,7 @@ func CreateCustomTableView(properties CustomTableViewProperties) *tview.Table {
 				cmd.UiState.NavigationStack = append(cmd.UiState.NavigationStack, cmd.NavigationState{
 					Type:  cmd.BreadcrumbJsonView,
 					Value: jsonViewLabel,
+					Row:   row,
 				})
 
 				// Create a callback that rebuilds the JSON viewer with the current or processed data
@@ -126,8 +130,8 @@ func CreateCustomTableView(properties CustomTableViewProperties) *tview.Table {
 					var dataToShow interface{}
 					var title string
 
-					// Determine what to show based on the last breadcrumb
-					if lastBreadcrumb == "Parsed JSON" || strings.HasPrefix(lastBreadcrumb, "Decompressed") {
+					// Determine what to show based on the current navigation level, parsed, decompressed or decoded data
+					if stackLen := len(cmd.UiState.NavigationStack); stackLen > 0 && cmd.UiState.NavigationStack[stackLen-1].Type == cmd.BreadcrumbProcessedJson {
 						// Get processed data from the current navigation state
 						if len(cmd.UiState.NavigationStack) > 0 {
 							currentNav := cmd.UiState.NavigationStack[len(cmd.UiState.NavigationStack)-1]
@@ -182,9 +186,7 @@ func CreateCustomTableView(properties CustomTableViewProperties) *tview.Table {
 				// Store the callback in UIState so ESC handler can use it
 				cmd.UiState.JsonViewerCallback = onBack
 
-				// Reset JSON format to DynamoDB style when first opening a viewer
-				cmd.UiState.ShowDynamoDBJsonFormat = true
-
+				// The viewer keeps the format of the table (toggled with 'v'), so both stay consistent
 				// Mark that we're in DynamoDB JSON viewer
 				cmd.UiState.InDynamoDBJsonViewer = true
 